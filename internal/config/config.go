@@ -13,21 +13,58 @@ import (
 const (
 	EnvLocal = "local"
 	EnvProd  = "prod"
+
+	WorkerBackendRedis    = "redis"
+	WorkerBackendRabbitMQ = "rabbitmq"
+
+	RateLimitKeyIP    = "ip"
+	RateLimitKeyUser  = "user"
+	RateLimitKeyEmail = "email"
+
+	TracingExporterOTLP   = "otlp"
+	TracingExporterStdout = "stdout"
 )
 
 type (
 	Config struct {
 		Environment string         `envconfig:"ENV"`
+		Logger      LoggerConfig   `mapstructure:"logger"`
 		Postgres    PostgresConfig `mapstructure:"postgresql"`
 		Redis       RedisConfig
-		HTTP        HTTPConfig  `mapstructure:"http"`
-		Auth        AuthConfig  `mapstructure:"auth"`
-		SMTP        SMTPConfig  `mapstructure:"smtp"`
-		Email       EmailConfig `mapstructure:"email"`
+		RabbitMQ    RabbitMQConfig    `mapstructure:"rabbitmq"`
+		Worker      WorkerConfig      `mapstructure:"worker"`
+		HTTP        HTTPConfig        `mapstructure:"http"`
+		GRPC        GRPCConfig        `mapstructure:"grpc"`
+		Auth        AuthConfig        `mapstructure:"auth"`
+		SMTP        SMTPConfig        `mapstructure:"smtp"`
+		Email       EmailConfig       `mapstructure:"email"`
+		I18n        I18nConfig        `mapstructure:"i18n"`
+		Webhooks    WebhooksConfig    `mapstructure:"webhooks"`
+		Cleanup     CleanupConfig     `mapstructure:"cleanup"`
+		RateLimit   RateLimitConfig   `mapstructure:"rate_limit"`
+		Health      HealthConfig      `mapstructure:"health"`
+		Shutdown    ShutdownConfig    `mapstructure:"shutdown"`
+		Metrics     MetricsConfig     `mapstructure:"metrics"`
+		AdminServer AdminServerConfig `mapstructure:"admin_server"`
+		Tracing     TracingConfig     `mapstructure:"tracing"`
+		Admin       AdminConfig
+	}
+
+	// LoggerConfig sets the default level and the levels of the packages,
+	// keyed by their import path relative to the module, such as
+	// "internal/worker". An empty format is json in prod and text otherwise.
+	// The values of the redact keys are masked on top of the secrets, tokens
+	// and emails that are always masked.
+	LoggerConfig struct {
+		Level      string            `mapstructure:"level"`
+		Format     string            `mapstructure:"format"`
+		AddSource  bool              `mapstructure:"add_source"`
+		Levels     map[string]string `mapstructure:"levels"`
+		RedactKeys []string          `mapstructure:"redact_keys"`
 	}
 
 	PostgresConfig struct {
-		URL          string        `envconfig:"POSTGRESQL_URL"`
+		URL          string        `envconfig:"POSTGRESQL_URL" redact:"true"`
 		MigrationURL string        `envconfig:"MIGRATION_URL"`
 		MaxAttempts  int           `mapstructure:"max_attempts"`
 		MaxDelay     time.Duration `mapstructure:"max_delay"`
@@ -37,30 +74,61 @@ type (
 		Address string `envconfig:"REDIS_ADDRESS"`
 	}
 
+	RabbitMQConfig struct {
+		URL            string        `envconfig:"RABBITMQ_URL" redact:"true"`
+		Exchange       string        `mapstructure:"exchange"`
+		TasksExchange  string        `mapstructure:"tasks_exchange"`
+		ReconnectDelay time.Duration `mapstructure:"reconnect_delay"`
+	}
+
+	// WorkerConfig bounds the wait for the tasks in flight on shutdown, the
+	// unfinished tasks are retried by the next worker.
+	WorkerConfig struct {
+		Backend         string        `mapstructure:"backend"`
+		ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	}
+
 	EmailConfig struct {
 		ServiceName     string         `envconfig:"EMAIL_SERVICE_NAME"`
 		ServiceAddress  string         `envconfig:"EMAIL_SERVICE_ADDRESS"`
-		ServicePassword string         `envconfig:"EMAIL_SERVICE_PASSWORD"`
+		ServicePassword string         `envconfig:"EMAIL_SERVICE_PASSWORD" redact:"true"`
 		Templates       EmailTemplates `mapstructure:"templates"`
-		Subjects        EmailSubjects  `mapstructure:"subjects"`
 	}
 
-	EmailTemplates struct {
-		VerifyEmail       string `mapstructure:"verify_email"`
-		LoginNotification string `mapstructure:"login_notification"`
+	// I18nConfig points to the catalogs of the messages, one yaml file per
+	// locale such as "en.yml". The templates of the emails take their texts
+	// and the subjects from the catalogs.
+	I18nConfig struct {
+		DefaultLocale string `mapstructure:"default_locale"`
+		LocalesDir    string `mapstructure:"locales_dir"`
 	}
 
-	EmailSubjects struct {
+	EmailTemplates struct {
 		VerifyEmail       string `mapstructure:"verify_email"`
 		LoginNotification string `mapstructure:"login_notification"`
 	}
 
 	AuthConfig struct {
-		JWT                    JWTConfig     `mapstructure:"jwt"`
-		SercetCodeLifetime     time.Duration `mapstructure:"sercetCodeLifetime"`
-		VerificationCodeLength int           `mapstructure:"verificationCodeLength"`
-		SecretKey              string        `envconfig:"SECRET_KEY"`
-		CodeSalt               string        `envconfig:"CODE_SALT"`
+		JWT                    JWTConfig      `mapstructure:"jwt"`
+		SercetCodeLifetime     time.Duration  `mapstructure:"sercetCodeLifetime"`
+		VerificationCodeLength int            `mapstructure:"verificationCodeLength"`
+		SendCode               SendCodeConfig `mapstructure:"sendCode"`
+		Cookies                CookiesConfig  `mapstructure:"cookies"`
+		SecretKey              string         `envconfig:"SECRET_KEY" redact:"true"`
+		CodeSalt               string         `envconfig:"CODE_SALT" redact:"true"`
+	}
+
+	SendCodeConfig struct {
+		Cooldown           time.Duration `mapstructure:"cooldown"`
+		DailyLimitPerEmail int           `mapstructure:"dailyLimitPerEmail"`
+		DailyLimitPerIP    int           `mapstructure:"dailyLimitPerIP"`
+	}
+
+	// CookiesConfig turns on the session cookies for the browser clients. The
+	// same site mode is strict unless it is lax or none.
+	CookiesConfig struct {
+		Enabled  bool   `mapstructure:"enabled"`
+		SameSite string `mapstructure:"sameSite"`
 	}
 
 	JWTConfig struct {
@@ -69,11 +137,142 @@ type (
 	}
 
 	HTTPConfig struct {
-		Host               string        `envconfig:"HTTP_HOST"`
-		Port               string        `mapstructure:"port"`
-		MaxHeaderMegabytes int           `mapstructure:"maxHeaderBytes"`
-		ReadTimeout        time.Duration `mapstructure:"readTimeout"`
-		WriteTimeout       time.Duration `mapstructure:"writeTimeout"`
+		Host               string                `envconfig:"HTTP_HOST"`
+		Port               string                `mapstructure:"port"`
+		MaxHeaderMegabytes int                   `mapstructure:"maxHeaderBytes"`
+		MaxBodyMegabytes   int                   `mapstructure:"maxBodyBytes"`
+		ReadTimeout        time.Duration         `mapstructure:"readTimeout"`
+		ReadHeaderTimeout  time.Duration         `mapstructure:"readHeaderTimeout"`
+		WriteTimeout       time.Duration         `mapstructure:"writeTimeout"`
+		IdleTimeout        time.Duration         `mapstructure:"idleTimeout"`
+		TrustedProxies     []string              `mapstructure:"trustedProxies"`
+		ClientIPHeaders    []string              `mapstructure:"clientIpHeaders"`
+		ProxyProtocol      bool                  `mapstructure:"proxyProtocol"`
+		CORS               CORSConfig            `mapstructure:"cors"`
+		SecurityHeaders    SecurityHeadersConfig `mapstructure:"securityHeaders"`
+		TLS                TLSConfig             `mapstructure:"tls"`
+	}
+
+	// TLSConfig serves https on the http port. The certificate of the files
+	// is loaded again once they change, checked at most once per reload
+	// interval, or autocert issues the certificates for the hosts. The redirect
+	// port sends plain http to https and answers the ACME challenges. The
+	// client certificates are verified against the client CA when given, or
+	// always when they are required.
+	TLSConfig struct {
+		Enabled           bool           `mapstructure:"enabled" envconfig:"TLS_ENABLED"`
+		CertFile          string         `mapstructure:"certFile" envconfig:"TLS_CERT_FILE"`
+		KeyFile           string         `mapstructure:"keyFile" envconfig:"TLS_KEY_FILE"`
+		ReloadInterval    time.Duration  `mapstructure:"reloadInterval"`
+		Autocert          AutocertConfig `mapstructure:"autocert"`
+		RedirectPort      string         `mapstructure:"redirectPort"`
+		ClientCAFile      string         `mapstructure:"clientCaFile" envconfig:"TLS_CLIENT_CA_FILE"`
+		RequireClientCert bool           `mapstructure:"requireClientCert"`
+	}
+
+	AutocertConfig struct {
+		Enabled  bool     `mapstructure:"enabled"`
+		Hosts    []string `mapstructure:"hosts" envconfig:"AUTOCERT_HOSTS"`
+		CacheDir string   `mapstructure:"cacheDir"`
+		Email    string   `mapstructure:"email" envconfig:"AUTOCERT_EMAIL"`
+	}
+
+	// SecurityHeadersConfig sets the headers of every response, the empty
+	// values and a zero HSTS max age leave the headers out.
+	SecurityHeadersConfig struct {
+		HSTSMaxAge            time.Duration `mapstructure:"hstsMaxAge"`
+		ContentSecurityPolicy string        `mapstructure:"contentSecurityPolicy"`
+		ReferrerPolicy        string        `mapstructure:"referrerPolicy"`
+	}
+
+	// CORSConfig allows the browser clients of the origins, exact such as
+	// "https://app.example.com" or of the subdomains such as
+	// "https://*.example.com". The origins differ between the environments and
+	// are usually set with CORS_ALLOWED_ORIGINS, the credentials that the
+	// session cookies need can not be allowed with the "*" origin.
+	CORSConfig struct {
+		AllowedOrigins   []string      `mapstructure:"allowedOrigins" envconfig:"CORS_ALLOWED_ORIGINS"`
+		AllowedMethods   []string      `mapstructure:"allowedMethods"`
+		AllowedHeaders   []string      `mapstructure:"allowedHeaders"`
+		ExposedHeaders   []string      `mapstructure:"exposedHeaders"`
+		AllowCredentials bool          `mapstructure:"allowCredentials" envconfig:"CORS_ALLOW_CREDENTIALS"`
+		MaxAge           time.Duration `mapstructure:"maxAge"`
+	}
+
+	GRPCConfig struct {
+		Port string `mapstructure:"port"`
+	}
+
+	WebhooksConfig struct {
+		Timeout     time.Duration `mapstructure:"timeout"`
+		MaxRetry    int           `mapstructure:"max_retry"`
+		MaxFailures int           `mapstructure:"max_failures"`
+		BackoffBase time.Duration `mapstructure:"backoff_base"`
+		BackoffMax  time.Duration `mapstructure:"backoff_max"`
+	}
+
+	CleanupConfig struct {
+		Schedule              string        `mapstructure:"schedule"`
+		BatchSize             int           `mapstructure:"batch_size"`
+		VerifyEmailsRetention time.Duration `mapstructure:"verify_emails_retention"`
+		SessionsRetention     time.Duration `mapstructure:"sessions_retention"`
+	}
+
+	RateLimitConfig struct {
+		Auth     RateLimitPolicy `mapstructure:"auth"`
+		SendCode RateLimitPolicy `mapstructure:"send_code"`
+		Users    RateLimitPolicy `mapstructure:"users"`
+		Admin    RateLimitPolicy `mapstructure:"admin"`
+	}
+
+	// RateLimitPolicy allows Requests per Period for every value of Key,
+	// zero requests disable the policy.
+	RateLimitPolicy struct {
+		Requests int           `mapstructure:"requests"`
+		Period   time.Duration `mapstructure:"period"`
+		Key      string        `mapstructure:"key"`
+	}
+
+	// HealthConfig bounds every readiness check by the timeout, the smtp
+	// server is checked only when asked to. On shutdown the service reports
+	// not ready for the drain delay before it stops accepting requests.
+	HealthConfig struct {
+		Timeout    time.Duration `mapstructure:"timeout"`
+		CheckSMTP  bool          `mapstructure:"check_smtp"`
+		DrainDelay time.Duration `mapstructure:"drain_delay"`
+	}
+
+	// ShutdownConfig bounds the stop of every component that has no timeout
+	// of its own, such as the servers and the clients.
+	ShutdownConfig struct {
+		Timeout time.Duration `mapstructure:"timeout"`
+	}
+
+	// MetricsConfig serves the metrics at the path of the admin server.
+	MetricsConfig struct {
+		Enabled bool   `mapstructure:"enabled"`
+		Path    string `mapstructure:"path"`
+	}
+
+	// AdminServerConfig binds the listener of the operational endpoints, such
+	// as pprof, the metrics and the log level. They are not authenticated, the
+	// address must not be reachable from outside.
+	AdminServerConfig struct {
+		Enabled bool   `mapstructure:"enabled"`
+		Address string `mapstructure:"address" envconfig:"ADMIN_SERVER_ADDRESS"`
+	}
+
+	// TracingConfig selects the span exporter, the otlp exporter is configured
+	// with the standard OTEL_EXPORTER_OTLP_* environment variables.
+	TracingConfig struct {
+		Enabled     bool    `mapstructure:"enabled"`
+		Exporter    string  `mapstructure:"exporter"`
+		ServiceName string  `mapstructure:"service_name"`
+		SampleRatio float64 `mapstructure:"sample_ratio"`
+	}
+
+	AdminConfig struct {
+		APIKey string `envconfig:"ADMIN_API_KEY" redact:"true"`
 	}
 
 	SMTPConfig struct {
@@ -14,6 +14,8 @@ type Session struct {
 	ClientIP     string    `json:"client_ip"`
 	IsBlocked    bool      `json:"is_blocked"`
 	ExpiresAt    time.Time `json:"expires_at"`
+	RequestID    string    `json:"request_id"`
+	DeviceID     string    `json:"device_id"`
 }
 
 type VerifyEmail struct {
@@ -3,12 +3,14 @@ package auth
 import "github.com/google/uuid"
 
 type SendCodeEmailInput struct {
-	Email string `json:"email"`
+	Email    string `json:"email"`
+	ClientIP string `json:"client_ip"`
 }
 
-func NewSendCodeEmailInput(email string) SendCodeEmailInput {
+func NewSendCodeEmailInput(email, clientIP string) SendCodeEmailInput {
 	return SendCodeEmailInput{
-		Email: email,
+		Email:    email,
+		ClientIP: clientIP,
 	}
 }
 
@@ -5,12 +5,32 @@ import "errors"
 var (
 	ErrConnectPostgreSQL = errors.New("all attempts are exceeded, unable to connect to PostgreSQL")
 
-	ErrInvalidInput = errors.New("invalid input body")
-	ErrInvalidEmail = errors.New("invalid email")
-	ErrIdentifier   = errors.New("invalid identifier type")
+	ErrRabbitMQNotConnected  = errors.New("rabbitmq connection is not established")
+	ErrPublishNotConfirmed   = errors.New("message publishing was not confirmed by broker")
+	ErrUnknownQueue          = errors.New("unknown task queue")
+	ErrUnknownWorkerBackend  = errors.New("unknown worker backend")
+	ErrUnknownTaskState      = errors.New("unknown task state")
+	ErrTaskNotFound          = errors.New("task not found")
+	ErrInspectorUnsupported  = errors.New("task inspection is not supported by the worker backend")
+	ErrUnsupportedTaskOption = errors.New("task option is not supported by the worker backend")
+
+	ErrUnknownTracingExporter = errors.New("unknown tracing exporter")
+	ErrUnknownLogLevel        = errors.New("unknown log level")
+	ErrUnknownLogFormat       = errors.New("unknown log format")
+	ErrUnsupportedLocale      = errors.New("unsupported locale")
+
+	ErrRouteNotFound    = errors.New("route not found")
+	ErrMethodNotAllowed = errors.New("method not allowed")
+
+	ErrInvalidInput    = errors.New("invalid input body")
+	ErrRequestTooLarge = errors.New("request body is too large")
+	ErrInvalidEmail    = errors.New("invalid email")
+	ErrIdentifier      = errors.New("invalid identifier type")
 
 	ErrEmptyAuthHeader         = errors.New("empty authorization header")
 	ErrInvalidAuthHeaderFormat = errors.New("invalid authorization header format")
+	ErrUnsupportedAuthType     = errors.New("unsupported authorization type")
+	ErrInvalidCSRFToken        = errors.New("invalid csrf token")
 
 	ErrInvalidToken = errors.New("invalid token")
 	ErrExpiredToken = errors.New("token has expired")
@@ -23,4 +43,17 @@ var (
 
 	ErrSecretCodeInvalid = errors.New("code is incorrect")
 	ErrSecretCodeExpired = errors.New("code is expired")
+
+	ErrSendCodeCooldown   = errors.New("code has been sent recently")
+	ErrSendCodeDailyLimit = errors.New("daily limit of sent codes is exceeded")
+	ErrRateLimitExceeded  = errors.New("too many requests")
+
+	ErrEmptyAdminKey   = errors.New("empty admin key header")
+	ErrInvalidAdminKey = errors.New("invalid admin key")
+
+	ErrWebhookNotFound         = errors.New("webhook subscription not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrUnknownEventType        = errors.New("unknown event type")
+	ErrInsecureWebhookURL      = errors.New("webhook url must use https")
+	ErrWebhookUnexpectedStatus = errors.New("webhook endpoint responded with unexpected status")
 )
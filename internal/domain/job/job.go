@@ -0,0 +1,16 @@
+package job
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Run struct {
+	ID          uuid.UUID `json:"id"`
+	Job         string    `json:"job"`
+	Target      string    `json:"target"`
+	RowsRemoved int64     `json:"rows_removed"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+}
@@ -0,0 +1,17 @@
+package task
+
+type ListTasksInput struct {
+	Queue    string `json:"queue"`
+	State    string `json:"state"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
+
+func NewListTasksInput(queue, state string, page, pageSize int) ListTasksInput {
+	return ListTasksInput{
+		Queue:    queue,
+		State:    state,
+		Page:     page,
+		PageSize: pageSize,
+	}
+}
@@ -0,0 +1,37 @@
+package task
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	StateRetry    = "retry"
+	StateArchived = "archived"
+)
+
+type Queue struct {
+	Name      string `json:"name"`
+	Size      int    `json:"size"`
+	Pending   int    `json:"pending"`
+	Active    int    `json:"active"`
+	Scheduled int    `json:"scheduled"`
+	Retry     int    `json:"retry"`
+	Archived  int    `json:"archived"`
+	Processed int    `json:"processed"`
+	Failed    int    `json:"failed"`
+	Paused    bool   `json:"paused"`
+}
+
+type Task struct {
+	ID            string          `json:"id"`
+	Queue         string          `json:"queue"`
+	Type          string          `json:"type"`
+	State         string          `json:"state"`
+	Payload       json.RawMessage `json:"payload"`
+	MaxRetry      int             `json:"max_retry"`
+	Retried       int             `json:"retried"`
+	LastError     string          `json:"last_error"`
+	LastFailedAt  time.Time       `json:"last_failed_at"`
+	NextProcessAt time.Time       `json:"next_process_at"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// ThrottledError is returned when an action is refused until RetryAfter has
+// passed.
+type ThrottledError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func NewThrottledError(err error, retryAfter time.Duration) *ThrottledError {
+	return &ThrottledError{
+		Err:        err,
+		RetryAfter: retryAfter,
+	}
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", e.Err, e.RetryAfter.Round(time.Second))
+}
+
+func (e *ThrottledError) Unwrap() error {
+	return e.Err
+}
@@ -9,5 +9,6 @@ import (
 type User struct {
 	ID        uuid.UUID `json:"id" binding:"required"`
 	Email     string    `json:"email" binding:"required"`
+	Locale    string    `json:"locale"`
 	CreatedAt time.Time `json:"created_at" binding:"required"`
 }
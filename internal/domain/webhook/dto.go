@@ -0,0 +1,15 @@
+package webhook
+
+type CreateSubscriptionInput struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types"`
+}
+
+func NewCreateSubscriptionInput(url, secret string, eventTypes []string) CreateSubscriptionInput {
+	return CreateSubscriptionInput{
+		URL:        url,
+		Secret:     secret,
+		EventTypes: eventTypes,
+	}
+}
@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusSucceeded = "succeeded"
+	DeliveryStatusFailed    = "failed"
+)
+
+type Subscription struct {
+	ID           uuid.UUID `json:"id"`
+	URL          string    `json:"url"`
+	Secret       string    `json:"secret"`
+	EventTypes   []string  `json:"event_types"`
+	IsActive     bool      `json:"is_active"`
+	FailureCount int       `json:"failure_count"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type Delivery struct {
+	ID             uuid.UUID       `json:"id"`
+	SubscriptionID uuid.UUID       `json:"subscription_id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	RequestID      string          `json:"request_id"`
+}
+
+type Attempt struct {
+	ID           uuid.UUID     `json:"id"`
+	DeliveryID   uuid.UUID     `json:"delivery_id"`
+	ResponseCode int           `json:"response_code"`
+	Error        string        `json:"error"`
+	Duration     time.Duration `json:"duration"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+type DeliveryWithAttempts struct {
+	Delivery
+	AttemptsLog []Attempt `json:"attempts_log"`
+}
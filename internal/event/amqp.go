@@ -0,0 +1,49 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/b0shka/backend/pkg/broker/rabbitmq"
+	"github.com/streadway/amqp"
+)
+
+const exchangeKind = "topic"
+
+type AMQPPublisher struct {
+	publisher *rabbitmq.Publisher
+	exchange  string
+}
+
+func NewAMQPPublisher(client *rabbitmq.Client, exchange string) Publisher {
+	return &AMQPPublisher{
+		publisher: rabbitmq.NewPublisher(client, func(channel *amqp.Channel) error {
+			return channel.ExchangeDeclare(exchange, exchangeKind, true, false, false, false, nil)
+		}),
+		exchange: exchange,
+	}
+}
+
+// Publish sends the event to the topic exchange using the event type as the
+// routing key and waits until the broker confirms it.
+func (p *AMQPPublisher) Publish(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	err = p.publisher.Publish(ctx, p.exchange, event.Type, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    event.ID.String(),
+		Type:         event.Type,
+		Timestamp:    event.OccurredAt,
+		Body:         body,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	TypeUserCreated    = "user.created"
+	TypeUserSignedIn   = "user.signed_in"
+	TypeSessionRevoked = "session.revoked"
+	TypeUserDeleted    = "user.deleted"
+)
+
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+type Event struct {
+	ID         uuid.UUID `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+func New(eventType string, data any) Event {
+	return Event{
+		ID:         uuid.New(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
+
+type UserCreated struct {
+	UserID uuid.UUID `json:"user_id"`
+	Email  string    `json:"email"`
+}
+
+type UserSignedIn struct {
+	UserID    uuid.UUID `json:"user_id"`
+	SessionID uuid.UUID `json:"session_id"`
+	UserAgent string    `json:"user_agent"`
+	ClientIP  string    `json:"client_ip"`
+}
+
+type SessionRevoked struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+type UserDeleted struct {
+	UserID uuid.UUID `json:"user_id"`
+	Email  string    `json:"email"`
+}
+
+// Types returns every event type a subscriber can be notified about.
+func Types() []string {
+	return []string{
+		TypeUserCreated,
+		TypeUserSignedIn,
+		TypeSessionRevoked,
+		TypeUserDeleted,
+	}
+}
+
+type MultiPublisher struct {
+	publishers []Publisher
+}
+
+// NewMultiPublisher returns a publisher that delivers every event to all
+// the given publishers.
+func NewMultiPublisher(publishers ...Publisher) Publisher {
+	return &MultiPublisher{
+		publishers: publishers,
+	}
+}
+
+func (p *MultiPublisher) Publish(ctx context.Context, event Event) error {
+	var errs []error
+
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,171 @@
+package admin
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/pprof"
+	"runtime/debug"
+
+	"github.com/b0shka/backend/internal/config"
+	"github.com/b0shka/backend/pkg/health"
+	"github.com/b0shka/backend/pkg/logger"
+	"github.com/b0shka/backend/pkg/redact"
+)
+
+// Handler serves the operational endpoints on the admin listener. They are
+// not authenticated, the listener must only be reachable from inside.
+type Handler struct {
+	cfg     *config.Config
+	probe   *health.Probe
+	logger  *slog.Logger
+	metrics http.Handler
+}
+
+// NewHandler serves the metrics of the handler when it is not nil, the log
+// level is changed on the logger and the loggers derived from it.
+func NewHandler(cfg *config.Config, probe *health.Probe, log *slog.Logger, metrics http.Handler) *Handler {
+	return &Handler{
+		cfg:     cfg,
+		probe:   probe,
+		logger:  log,
+		metrics: metrics,
+	}
+}
+
+func (h *Handler) InitRoutes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	if h.metrics != nil {
+		mux.Handle(h.cfg.Metrics.Path, h.metrics)
+	}
+
+	mux.HandleFunc("/readyz", h.readyz)
+	mux.HandleFunc("/version", h.version)
+	mux.HandleFunc("/log/level", h.logLevel)
+	mux.HandleFunc("/config", h.config)
+
+	return mux
+}
+
+func (h *Handler) readyz(w http.ResponseWriter, r *http.Request) {
+	report, ready := h.probe.Check(r.Context())
+	if !ready {
+		writeJSON(w, http.StatusServiceUnavailable, report)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+type versionResponse struct {
+	Version   string `json:"version"`
+	Revision  string `json:"revision,omitempty"`
+	Time      string `json:"time,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// version reports the build from the information the go toolchain embeds,
+// the revision is only known when the binary is built from the repository.
+func (h *Handler) version(w http.ResponseWriter, _ *http.Request) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		writeError(w, http.StatusNotFound, "build info is not available")
+
+		return
+	}
+
+	res := versionResponse{
+		Version:   info.Main.Version,
+		GoVersion: info.GoVersion,
+	}
+
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			res.Revision = setting.Value
+		case "vcs.time":
+			res.Time = setting.Value
+		case "vcs.modified":
+			res.Modified = setting.Value == "true"
+		}
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+type logLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// logLevel reports the default level of the logs on GET and changes it on
+// PUT, the levels of the packages stay as configured. The change is lost on
+// restart.
+func (h *Handler) logLevel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var req logLevelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid input body")
+
+			return
+		}
+
+		level, err := logger.ParseLevel(req.Level)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		if !logger.SetLevel(h.logger, level) {
+			writeError(w, http.StatusNotImplemented, "the logger does not support changing the level")
+
+			return
+		}
+
+		slog.Info("log level changed", "level", level.String())
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+
+		return
+	}
+
+	level, ok := logger.Level(h.logger)
+	if !ok {
+		writeError(w, http.StatusNotImplemented, "the logger does not support changing the level")
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, logLevelRequest{Level: level.String()})
+}
+
+// config dumps the configuration the service runs with, the secrets and the
+// configured redact keys are masked.
+func (h *Handler) config(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, redact.NewRedactor(h.cfg.Logger.RedactKeys...).Value(h.cfg))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("failed to write admin response", logger.Err(err))
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
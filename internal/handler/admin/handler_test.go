@@ -0,0 +1,162 @@
+package admin
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/b0shka/backend/internal/config"
+	"github.com/b0shka/backend/pkg/health"
+	"github.com/b0shka/backend/pkg/logger"
+	"github.com/b0shka/backend/pkg/redact"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHandler(t *testing.T, metrics http.Handler) http.Handler {
+	t.Helper()
+
+	cfg := &config.Config{}
+	cfg.Logger.Level = "info"
+	cfg.Metrics.Path = "/metrics"
+	cfg.Auth.SecretKey = "secret"
+
+	log, err := logger.New(io.Discard, cfg.Logger, config.EnvLocal)
+	require.NoError(t, err)
+
+	return NewHandler(cfg, health.NewProbe(time.Second), log, metrics).InitRoutes()
+}
+
+func serve(handler http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, target, strings.NewReader(body)))
+
+	return recorder
+}
+
+func TestHandler_logLevel(t *testing.T) {
+	handler := newTestHandler(t, nil)
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		statusCode int
+		level      string
+	}{
+		{
+			name:       "get",
+			method:     http.MethodGet,
+			statusCode: http.StatusOK,
+			level:      slog.LevelInfo.String(),
+		},
+		{
+			name:       "put",
+			method:     http.MethodPut,
+			body:       `{"level":"debug"}`,
+			statusCode: http.StatusOK,
+			level:      slog.LevelDebug.String(),
+		},
+		{
+			name:       "get changed",
+			method:     http.MethodGet,
+			statusCode: http.StatusOK,
+			level:      slog.LevelDebug.String(),
+		},
+		{
+			name:       "invalid level",
+			method:     http.MethodPut,
+			body:       `{"level":"verbose"}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid body",
+			method:     http.MethodPut,
+			body:       `level`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodPost,
+			statusCode: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			recorder := serve(handler, testCase.method, "/log/level", testCase.body)
+			require.Equal(t, testCase.statusCode, recorder.Code)
+
+			if testCase.level == "" {
+				return
+			}
+
+			var res logLevelRequest
+
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(&res))
+			require.Equal(t, testCase.level, res.Level)
+		})
+	}
+}
+
+func TestHandler_config(t *testing.T) {
+	handler := newTestHandler(t, nil)
+
+	recorder := serve(handler, http.MethodGet, "/config", "")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NotContains(t, recorder.Body.String(), `"secret"`)
+	require.Contains(t, recorder.Body.String(), redact.Mask)
+}
+
+func TestHandler_routes(t *testing.T) {
+	metrics := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "metrics")
+	})
+
+	tests := []struct {
+		name       string
+		metrics    http.Handler
+		target     string
+		statusCode int
+	}{
+		{
+			name:       "readyz",
+			target:     "/readyz",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "version",
+			target:     "/version",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "pprof",
+			target:     "/debug/pprof/",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "metrics",
+			metrics:    metrics,
+			target:     "/metrics",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "metrics disabled",
+			target:     "/metrics",
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			handler := newTestHandler(t, testCase.metrics)
+
+			recorder := serve(handler, http.MethodGet, testCase.target, "")
+			require.Equal(t, testCase.statusCode, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"context"
+	"net/mail"
+
+	"github.com/b0shka/backend/internal/domain"
+	domain_auth "github.com/b0shka/backend/internal/domain/auth"
+	"github.com/b0shka/backend/internal/handler/grpc/pb"
+	"github.com/b0shka/backend/pkg/clientinfo"
+)
+
+const secretCodeLength = 6
+
+func (h *Handler) SendCode(ctx context.Context, req *pb.SendCodeRequest) (*pb.SendCodeResponse, error) {
+	if err := validateEmail(req.GetEmail()); err != nil {
+		return nil, err
+	}
+
+	err := h.services.Auth.SendCodeEmail(ctx, domain_auth.NewSendCodeEmailInput(req.GetEmail(), clientinfo.FromContext(ctx).IP))
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.SendCodeResponse{}, nil
+}
+
+func (h *Handler) SignInUser(ctx context.Context, req *pb.SignInUserRequest) (*pb.SignInUserResponse, error) {
+	if err := validateEmail(req.GetEmail()); err != nil {
+		return nil, err
+	}
+
+	if len(req.GetSecretCode()) != secretCodeLength {
+		return nil, domain.ErrInvalidInput
+	}
+
+	res, err := h.services.Auth.SignIn(ctx, domain_auth.NewSignInInput(req.GetEmail(), req.GetSecretCode()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.SignInUserResponse{
+		SessionId:    res.SessionID.String(),
+		RefreshToken: res.RefreshToken,
+		AccessToken:  res.AccessToken,
+	}, nil
+}
+
+func (h *Handler) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.RefreshTokenResponse, error) {
+	if req.GetRefreshToken() == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	res, err := h.services.Auth.RefreshToken(ctx, domain_auth.NewRefreshTokenInput(req.GetRefreshToken()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RefreshTokenResponse{
+		AccessToken: res.AccessToken,
+	}, nil
+}
+
+// validateEmail rejects the input as a whole like the REST api always did,
+// the clients can not tell a malformed email from a missing one.
+func validateEmail(email string) error {
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return domain.ErrInvalidInput
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/b0shka/backend/internal/domain"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	// errorDomain qualifies the reasons of the ErrorInfo details, the reasons
+	// are the codes of the problems of the http api.
+	errorDomain = "backend"
+
+	reasonInternalError = "internal_error"
+)
+
+type statusMapping struct {
+	err    error
+	code   codes.Code
+	reason string
+}
+
+// statusMappings maps the domain errors to the statuses, the first match
+// wins. The errors that match none are internal and never shown.
+var statusMappings = []statusMapping{ //nolint: gochecknoglobals
+	{domain.ErrInvalidInput, codes.InvalidArgument, "invalid_input"},
+	{domain.ErrInvalidEmail, codes.InvalidArgument, "invalid_email"},
+	{domain.ErrIdentifier, codes.InvalidArgument, "invalid_identifier"},
+	{domain.ErrUnsupportedLocale, codes.InvalidArgument, "unsupported_locale"},
+
+	{domain.ErrEmptyAuthHeader, codes.Unauthenticated, "empty_authorization_header"},
+	{domain.ErrInvalidAuthHeaderFormat, codes.Unauthenticated, "invalid_authorization_header"},
+	{domain.ErrUnsupportedAuthType, codes.Unauthenticated, "unsupported_authorization_type"},
+	{domain.ErrInvalidToken, codes.Unauthenticated, "invalid_token"},
+	{domain.ErrExpiredToken, codes.Unauthenticated, "expired_token"},
+	{domain.ErrSecretCodeInvalid, codes.Unauthenticated, "invalid_code"},
+	{domain.ErrSecretCodeExpired, codes.Unauthenticated, "expired_code"},
+	{domain.ErrSessionBlocked, codes.Unauthenticated, "session_blocked"},
+	{domain.ErrIncorrectSessionUser, codes.Unauthenticated, "incorrect_session_user"},
+	{domain.ErrMismatchedSession, codes.Unauthenticated, "mismatched_session"},
+
+	{domain.ErrUserNotFound, codes.NotFound, "user_not_found"},
+	{domain.ErrSessionNotFound, codes.NotFound, "session_not_found"},
+
+	{domain.ErrSendCodeCooldown, codes.ResourceExhausted, "send_code_cooldown"},
+	{domain.ErrSendCodeDailyLimit, codes.ResourceExhausted, "send_code_daily_limit"},
+	{domain.ErrRateLimitExceeded, codes.ResourceExhausted, "rate_limit_exceeded"},
+}
+
+// newStatus turns the error into the status of the call. The reason of the
+// error and the retry delay of the throttled errors are attached as details.
+func newStatus(err error) *status.Status {
+	for _, mapping := range statusMappings {
+		if !errors.Is(err, mapping.err) {
+			continue
+		}
+
+		st := status.New(mapping.code, err.Error())
+		info := &errdetails.ErrorInfo{Reason: mapping.reason, Domain: errorDomain}
+
+		var throttledErr *domain.ThrottledError
+		if errors.As(err, &throttledErr) {
+			retryAfter := time.Duration(math.Ceil(throttledErr.RetryAfter.Seconds())) * time.Second
+
+			return withDetails(st, info, &errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+		}
+
+		return withDetails(st, info)
+	}
+
+	return withDetails(
+		status.New(codes.Internal, "internal error"),
+		&errdetails.ErrorInfo{Reason: reasonInternalError, Domain: errorDomain},
+	)
+}
+
+// withDetails keeps the status without the details when they fail to
+// marshal, which never happens for the errdetails messages.
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) *status.Status {
+	res, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+
+	return res
+}
@@ -0,0 +1,90 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+
+	"github.com/b0shka/backend/pkg/logger"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	// MetadataClientIP and MetadataUserAgent carry the client of the REST
+	// request to the call, they are trusted from the gateway connection only.
+	MetadataClientIP  = "x-gateway-client-ip"
+	MetadataUserAgent = "x-gateway-user-agent"
+
+	gatewayBufferSize = 1 << 20
+)
+
+// gatewayAddr is the peer address of the calls made through the gateway,
+// the clients of the gRPC listener can not forge it.
+type gatewayAddr struct{}
+
+func (gatewayAddr) Network() string { return "gateway" }
+func (gatewayAddr) String() string  { return "gateway" }
+
+type gatewayListener struct {
+	*bufconn.Listener
+}
+
+func (l gatewayListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	return gatewayConn{conn}, nil
+}
+
+type gatewayConn struct {
+	net.Conn
+}
+
+func (gatewayConn) RemoteAddr() net.Addr {
+	return gatewayAddr{}
+}
+
+// DialGateway serves the server on an in-memory listener and dials it, so
+// that the REST gateway calls the api through the interceptors without a
+// network hop. The listener is closed when the server stops.
+func DialGateway(server *grpc.Server) (*grpc.ClientConn, error) {
+	listener := bufconn.Listen(gatewayBufferSize)
+
+	go func() {
+		if err := server.Serve(gatewayListener{listener}); err != nil {
+			slog.Error("failed to serve gateway", logger.Err(err))
+		}
+	}()
+
+	conn, err := grpc.Dial(
+		"passthrough:///gateway",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial gateway: %w", err)
+	}
+
+	return conn, nil
+}
+
+func fromGateway(ctx context.Context) bool {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return false
+	}
+
+	_, ok = p.Addr.(gatewayAddr)
+
+	return ok
+}
@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"github.com/b0shka/backend/internal/config"
+	"github.com/b0shka/backend/internal/handler/grpc/pb"
+	"github.com/b0shka/backend/internal/service"
+	"github.com/b0shka/backend/pkg/auth"
+	"github.com/b0shka/backend/pkg/i18n"
+	"github.com/b0shka/backend/pkg/limiter"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// Handler serves the Backend gRPC service over the same services as the
+// http handler.
+type Handler struct {
+	pb.UnimplementedBackendServer
+
+	services     *service.Services
+	tokenManager auth.Manager
+	limiter      limiter.Limiter
+	rateLimits   config.RateLimitConfig
+	i18n         *i18n.Bundle
+}
+
+func NewHandler(
+	services *service.Services,
+	tokenManager auth.Manager,
+	limiter limiter.Limiter,
+	rateLimits config.RateLimitConfig,
+	bundle *i18n.Bundle,
+) *Handler {
+	return &Handler{
+		services:     services,
+		tokenManager: tokenManager,
+		limiter:      limiter,
+		rateLimits:   rateLimits,
+		i18n:         bundle,
+	}
+}
+
+// NewServer registers the handler on a server with the interceptors. The
+// reflection service lets grpcurl and the like discover the api outside of
+// prod, as the swagger of the http api does.
+func (h *Handler) NewServer(cfg *config.Config) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			recoveryInterceptor,
+			requestIDInterceptor,
+			clientInfoInterceptor,
+			localeInterceptor(h.i18n),
+			loggerInterceptor,
+			errorInterceptor,
+			authInterceptor(h.tokenManager),
+			rateLimitInterceptor(h.limiter, h.rateLimits),
+		),
+	)
+
+	pb.RegisterBackendServer(server, h)
+
+	if cfg.Environment != config.EnvProd {
+		reflection.Register(server)
+	}
+
+	return server
+}
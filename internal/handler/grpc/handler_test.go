@@ -0,0 +1,429 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/b0shka/backend/internal/config"
+	"github.com/b0shka/backend/internal/domain"
+	domain_auth "github.com/b0shka/backend/internal/domain/auth"
+	domain_user "github.com/b0shka/backend/internal/domain/user"
+	"github.com/b0shka/backend/internal/handler/grpc/pb"
+	"github.com/b0shka/backend/internal/service"
+	mock_service "github.com/b0shka/backend/internal/service/mocks"
+	"github.com/b0shka/backend/pkg/auth"
+	"github.com/b0shka/backend/pkg/clientinfo"
+	"github.com/b0shka/backend/pkg/limiter"
+	"github.com/b0shka/backend/pkg/logger"
+	"github.com/b0shka/backend/pkg/requestid"
+	"github.com/b0shka/backend/pkg/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var ErrInternalServerError = errors.New("test: internal server error")
+
+// newTestClient serves the handler over an in-memory connection.
+func newTestClient(t *testing.T, services *service.Services, tokenManager auth.Manager) pb.BackendClient {
+	t.Helper()
+
+	return newTestHandlerClient(t, NewHandler(services, tokenManager, nil, config.RateLimitConfig{}, nil))
+}
+
+func newTestHandlerClient(t *testing.T, handler *Handler) pb.BackendClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := handler.NewServer(&config.Config{})
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(
+		context.Background(),
+		"bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	return pb.NewBackendClient(conn)
+}
+
+func newTestTokenManager(t *testing.T) auth.Manager {
+	t.Helper()
+
+	secretKey, err := utils.RandomString(32)
+	require.NoError(t, err)
+
+	tokenManager, err := auth.NewJWTManager(secretKey)
+	require.NoError(t, err)
+
+	return tokenManager
+}
+
+func withAccessToken(t *testing.T, tokenManager auth.Manager, userID uuid.UUID) context.Context {
+	t.Helper()
+
+	token, _, err := tokenManager.CreateToken(userID, time.Minute)
+	require.NoError(t, err)
+
+	return metadata.AppendToOutgoingContext(context.Background(), authorizationMetadataKey, "Bearer "+token)
+}
+
+func requireStatus(t *testing.T, err error, code codes.Code, reason string) *status.Status {
+	t.Helper()
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, code, st.Code())
+
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			require.Equal(t, reason, info.GetReason())
+
+			return st
+		}
+	}
+
+	t.Fatalf("status %s has no error info", st)
+
+	return st
+}
+
+func TestHandler_SendCode(t *testing.T) {
+	tests := []struct {
+		name         string
+		email        string
+		mockBehavior func(s *mock_service.MockAuth)
+		code         codes.Code
+		reason       string
+	}{
+		{
+			name:  "ok",
+			email: "email@ya.ru",
+			mockBehavior: func(s *mock_service.MockAuth) {
+				s.EXPECT().
+					SendCodeEmail(gomock.Any(), domain_auth.NewSendCodeEmailInput("email@ya.ru", "bufconn")).
+					Return(nil)
+			},
+			code: codes.OK,
+		},
+		{
+			name:  "invalid email",
+			email: "email",
+			mockBehavior: func(s *mock_service.MockAuth) {
+				s.EXPECT().SendCodeEmail(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codes.InvalidArgument,
+			reason: "invalid_input",
+		},
+		{
+			name:  "cooldown",
+			email: "email@ya.ru",
+			mockBehavior: func(s *mock_service.MockAuth) {
+				s.EXPECT().
+					SendCodeEmail(gomock.Any(), gomock.Any()).
+					Return(domain.NewThrottledError(domain.ErrSendCodeCooldown, time.Second*30))
+			},
+			code:   codes.ResourceExhausted,
+			reason: "send_code_cooldown",
+		},
+		{
+			name:  "internal error",
+			email: "email@ya.ru",
+			mockBehavior: func(s *mock_service.MockAuth) {
+				s.EXPECT().SendCodeEmail(gomock.Any(), gomock.Any()).Return(ErrInternalServerError)
+			},
+			code:   codes.Internal,
+			reason: reasonInternalError,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			defer mockCtl.Finish()
+
+			authService := mock_service.NewMockAuth(mockCtl)
+			testCase.mockBehavior(authService)
+
+			client := newTestClient(t, &service.Services{Auth: authService}, newTestTokenManager(t))
+
+			_, err := client.SendCode(context.Background(), &pb.SendCodeRequest{Email: testCase.email})
+			if testCase.code == codes.OK {
+				require.NoError(t, err)
+
+				return
+			}
+
+			st := requireStatus(t, err, testCase.code, testCase.reason)
+			require.NotContains(t, st.Message(), ErrInternalServerError.Error())
+		})
+	}
+}
+
+func TestHandler_SendCodeRetryInfo(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	authService := mock_service.NewMockAuth(mockCtl)
+	authService.EXPECT().
+		SendCodeEmail(gomock.Any(), gomock.Any()).
+		Return(domain.NewThrottledError(domain.ErrSendCodeCooldown, time.Millisecond*29500))
+
+	client := newTestClient(t, &service.Services{Auth: authService}, newTestTokenManager(t))
+
+	_, err := client.SendCode(context.Background(), &pb.SendCodeRequest{Email: "email@ya.ru"})
+	st := requireStatus(t, err, codes.ResourceExhausted, "send_code_cooldown")
+
+	var retryInfo *errdetails.RetryInfo
+
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			retryInfo = info
+		}
+	}
+
+	require.NotNil(t, retryInfo)
+	require.Equal(t, time.Second*30, retryInfo.GetRetryDelay().AsDuration())
+}
+
+func TestHandler_SignInUser(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	var info clientinfo.Info
+
+	sessionID := uuid.New()
+	authService := mock_service.NewMockAuth(mockCtl)
+	authService.EXPECT().
+		SignIn(gomock.Any(), domain_auth.NewSignInInput("email@ya.ru", "123456")).
+		DoAndReturn(func(ctx context.Context, _ domain_auth.SignInInput) (domain_auth.SignInOutput, error) {
+			info = clientinfo.FromContext(ctx)
+
+			return domain_auth.NewSignInOutput(sessionID, "refresh", "access"), nil
+		})
+
+	client := newTestClient(t, &service.Services{Auth: authService}, newTestTokenManager(t))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), clientinfo.DeviceIDHeader, "device")
+	res, err := client.SignInUser(ctx, &pb.SignInUserRequest{Email: "email@ya.ru", SecretCode: "123456"})
+	require.NoError(t, err)
+	require.Equal(t, sessionID.String(), res.GetSessionId())
+	require.Equal(t, "refresh", res.GetRefreshToken())
+	require.Equal(t, "access", res.GetAccessToken())
+	require.Equal(t, "bufconn", info.IP)
+	require.Contains(t, info.UserAgent, "grpc-go")
+	require.Equal(t, "device", info.DeviceID)
+	require.NotEmpty(t, info.RequestID)
+
+	_, err = client.SignInUser(context.Background(), &pb.SignInUserRequest{Email: "email@ya.ru", SecretCode: "123"})
+	requireStatus(t, err, codes.InvalidArgument, "invalid_input")
+}
+
+func TestHandler_GetUser(t *testing.T) {
+	user := domain_user.User{
+		ID:        uuid.New(),
+		Email:     "email@ya.ru",
+		Locale:    "ru",
+		CreatedAt: time.Now(),
+	}
+
+	tests := []struct {
+		name         string
+		authorize    bool
+		mockBehavior func(s *mock_service.MockUsers)
+		code         codes.Code
+		reason       string
+	}{
+		{
+			name:      "ok",
+			authorize: true,
+			mockBehavior: func(s *mock_service.MockUsers) {
+				s.EXPECT().GetByID(gomock.Any(), user.ID).Return(user, nil)
+			},
+			code: codes.OK,
+		},
+		{
+			name: "no authorization",
+			mockBehavior: func(s *mock_service.MockUsers) {
+				s.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codes.Unauthenticated,
+			reason: "empty_authorization_header",
+		},
+		{
+			name:      "user not found",
+			authorize: true,
+			mockBehavior: func(s *mock_service.MockUsers) {
+				s.EXPECT().GetByID(gomock.Any(), user.ID).Return(domain_user.User{}, domain.ErrUserNotFound)
+			},
+			code:   codes.NotFound,
+			reason: "user_not_found",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			defer mockCtl.Finish()
+
+			usersService := mock_service.NewMockUsers(mockCtl)
+			testCase.mockBehavior(usersService)
+
+			tokenManager := newTestTokenManager(t)
+			client := newTestClient(t, &service.Services{Users: usersService}, tokenManager)
+
+			ctx := context.Background()
+			if testCase.authorize {
+				ctx = withAccessToken(t, tokenManager, user.ID)
+			}
+
+			res, err := client.GetUser(ctx, &pb.GetUserRequest{})
+			if testCase.code != codes.OK {
+				requireStatus(t, err, testCase.code, testCase.reason)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, user.ID.String(), res.GetUser().GetId())
+			require.Equal(t, user.Email, res.GetUser().GetEmail())
+			require.Equal(t, user.Locale, res.GetUser().GetLocale())
+			require.True(t, user.CreatedAt.Equal(res.GetUser().GetCreatedAt().AsTime()))
+		})
+	}
+}
+
+func TestHandler_DeleteUser(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	userID := uuid.New()
+	usersService := mock_service.NewMockUsers(mockCtl)
+	usersService.EXPECT().Delete(gomock.Any(), userID).Return(nil)
+
+	tokenManager := newTestTokenManager(t)
+	client := newTestClient(t, &service.Services{Users: usersService}, tokenManager)
+
+	var header metadata.MD
+
+	_, err := client.DeleteUser(withAccessToken(t, tokenManager, userID), &pb.DeleteUserRequest{}, grpc.Header(&header))
+	require.NoError(t, err)
+	require.True(t, requestid.Valid(header.Get(requestid.Header)[0]))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), authorizationMetadataKey, "Basic token")
+	_, err = client.DeleteUser(ctx, &pb.DeleteUserRequest{})
+	requireStatus(t, err, codes.Unauthenticated, "unsupported_authorization_type")
+}
+
+func TestHandler_rateLimit(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	authService := mock_service.NewMockAuth(mockCtl)
+	authService.EXPECT().SendCodeEmail(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	rateLimits := config.RateLimitConfig{
+		Auth:     config.RateLimitPolicy{Requests: 4, Period: time.Minute, Key: config.RateLimitKeyIP},
+		SendCode: config.RateLimitPolicy{Requests: 1, Period: time.Minute, Key: config.RateLimitKeyEmail},
+	}
+	handler := NewHandler(&service.Services{Auth: authService}, nil, limiter.NewMemoryLimiter(), rateLimits, nil)
+	client := newTestHandlerClient(t, handler)
+
+	_, err := client.SendCode(context.Background(), &pb.SendCodeRequest{Email: "email@ya.ru"})
+	require.NoError(t, err)
+
+	_, err = client.SendCode(context.Background(), &pb.SendCodeRequest{Email: "Email@ya.ru"})
+	requireStatus(t, err, codes.ResourceExhausted, "rate_limit_exceeded")
+
+	_, err = client.SendCode(context.Background(), &pb.SendCodeRequest{Email: "other@ya.ru"})
+	require.NoError(t, err)
+
+	// the sign in shares the budget of the ip with the send code.
+	_, err = client.SignInUser(context.Background(), &pb.SignInUserRequest{Email: "email@ya.ru", SecretCode: "123"})
+	requireStatus(t, err, codes.InvalidArgument, "invalid_input")
+
+	_, err = client.SignInUser(context.Background(), &pb.SignInUserRequest{Email: "email@ya.ru", SecretCode: "123"})
+	requireStatus(t, err, codes.ResourceExhausted, "rate_limit_exceeded")
+}
+
+func TestHandler_recovery(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	userID := uuid.New()
+
+	usersService := mock_service.NewMockUsers(mockCtl)
+	usersService.EXPECT().
+		GetByID(gomock.Any(), userID).
+		DoAndReturn(func(context.Context, uuid.UUID) (domain_user.User, error) {
+			var user *domain_user.User
+
+			return *user, nil
+		}).
+		Times(2)
+
+	tokenManager := newTestTokenManager(t)
+	client := newTestClient(t, &service.Services{Users: usersService}, tokenManager)
+
+	// the server keeps serving the calls after a handler panics.
+	for i := 0; i < 2; i++ {
+		_, err := client.GetUser(withAccessToken(t, tokenManager, userID), &pb.GetUserRequest{})
+		requireStatus(t, err, codes.Internal, reasonInternalError)
+	}
+}
+
+func TestHandler_NewServerReflection(t *testing.T) {
+	handler := NewHandler(&service.Services{}, nil, nil, config.RateLimitConfig{}, nil)
+
+	for env, registered := range map[string]bool{config.EnvLocal: true, config.EnvProd: false} {
+		server := handler.NewServer(&config.Config{Environment: env})
+		_, ok := server.GetServiceInfo()["grpc.reflection.v1.ServerReflection"]
+
+		require.Equal(t, registered, ok, env)
+	}
+}
+
+func TestInterceptors_logCode(t *testing.T) {
+	var buf bytes.Buffer
+
+	log, err := logger.New(&buf, config.LoggerConfig{Level: "debug"}, config.EnvProd)
+	require.NoError(t, err)
+
+	info := &grpc.UnaryServerInfo{FullMethod: pb.Backend_GetUser_FullMethodName}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return errorInterceptor(ctx, req, info, func(context.Context, any) (any, error) {
+			return nil, domain.ErrUserNotFound
+		})
+	}
+
+	_, err = loggerInterceptor(logger.WithContext(context.Background(), log), nil, info, handler)
+	require.Error(t, err)
+
+	// both the failed and the handled call are logged with the code.
+	require.Equal(t, 2, strings.Count(buf.String(), `"grpc_code":"NotFound"`))
+}
@@ -0,0 +1,221 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/b0shka/backend/internal/domain"
+	"github.com/b0shka/backend/internal/handler/grpc/pb"
+	"github.com/b0shka/backend/pkg/auth"
+	"github.com/b0shka/backend/pkg/clientinfo"
+	"github.com/b0shka/backend/pkg/i18n"
+	"github.com/b0shka/backend/pkg/logger"
+	"github.com/b0shka/backend/pkg/requestid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	authorizationMetadataKey  = "authorization"
+	authorizationTypeBearer   = "bearer"
+	acceptLanguageMetadataKey = "accept-language"
+)
+
+// authorizedMethods are the methods called on behalf of a user.
+var authorizedMethods = map[string]bool{ //nolint: gochecknoglobals
+	pb.Backend_GetUser_FullMethodName:          true,
+	pb.Backend_UpdateUserLocale_FullMethodName: true,
+	pb.Backend_DeleteUser_FullMethodName:       true,
+}
+
+type payloadKey struct{}
+
+// recoveryInterceptor answers the calls whose handler panics with an internal
+// status, as gin.Recovery does for the http api. grpc-go leaves the panics to
+// crash the process.
+func recoveryInterceptor(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (res any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.FromContext(ctx).Error(
+				"call panicked",
+				"method", info.FullMethod,
+				"panic", fmt.Sprint(r),
+				"stack", string(debug.Stack()),
+			)
+
+			res, err = nil, newStatus(fmt.Errorf("panic: %v", r)).Err()
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// requestIDInterceptor keeps the request ID sent by the client in the
+// metadata, or generates one, and returns it in the response header.
+func requestIDInterceptor(
+	ctx context.Context,
+	req any,
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	id := metadataValue(ctx, requestid.Header)
+	if !requestid.Valid(id) {
+		id = requestid.New()
+	}
+
+	ctx = requestid.WithContext(ctx, id)
+	ctx = logger.With(ctx, logger.KeyRequestID, id)
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("rpc.request_id", id))
+
+	if err := grpc.SetHeader(ctx, metadata.Pairs(requestid.Header, id)); err != nil {
+		logger.FromContext(ctx).Debug("failed to set request id header", logger.Err(err))
+	}
+
+	return handler(ctx, req)
+}
+
+// clientInfoInterceptor puts the client of the call in the context for the
+// services.
+func clientInfoInterceptor(
+	ctx context.Context,
+	req any,
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	ctx = clientinfo.WithContext(ctx, clientinfo.Info{
+		IP:        clientIP(ctx),
+		UserAgent: userAgent(ctx),
+		DeviceID:  deviceID(ctx),
+		RequestID: requestid.FromContext(ctx),
+	})
+
+	return handler(ctx, req)
+}
+
+// localeInterceptor negotiates the locale of the call from the
+// accept-language metadata, the tasks carry it so that the emails of the users
+// without a saved preference are rendered in it.
+func localeInterceptor(bundle *i18n.Bundle) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if bundle == nil {
+			return handler(ctx, req)
+		}
+
+		locale := bundle.Match(metadataValue(ctx, acceptLanguageMetadataKey))
+
+		return handler(i18n.WithLocale(ctx, locale), req)
+	}
+}
+
+// loggerInterceptor puts the call logger in the context and logs every
+// handled call.
+func loggerInterceptor(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	start := time.Now()
+	log := logger.FromContext(ctx).With(
+		"method", info.FullMethod,
+		"client_ip", clientinfo.FromContext(ctx).IP,
+	)
+
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		log = log.With("trace_id", spanContext.TraceID().String())
+	}
+
+	ctx = logger.WithContext(ctx, log)
+	res, err := handler(ctx, req)
+
+	log.Info("handled call", "grpc_code", status.Code(err).String(), "latency", time.Since(start))
+
+	return res, err
+}
+
+// errorInterceptor turns the errors of the handlers into statuses. The
+// internal errors are logged and answered with a generic status, so that the
+// SQL and the driver errors never reach the clients.
+func errorInterceptor(
+	ctx context.Context,
+	req any,
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	res, err := handler(ctx, req)
+	if err == nil {
+		return res, nil
+	}
+
+	st := newStatus(err)
+
+	level := slog.LevelWarn
+	if st.Code() == codes.Internal {
+		level = slog.LevelError
+	}
+
+	logger.FromContext(ctx).Log(ctx, level, "call failed", "grpc_code", st.Code().String(), logger.Err(err))
+
+	return nil, st.Err()
+}
+
+// authInterceptor verifies the access token of the calls of the authorized
+// methods and puts its payload in the context.
+func authInterceptor(tokenManager auth.Manager) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !authorizedMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		payload, err := parseAuthMetadata(ctx, tokenManager)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx = context.WithValue(ctx, payloadKey{}, payload)
+		ctx = logger.With(ctx, logger.KeyUserID, payload.UserID)
+
+		return handler(ctx, req)
+	}
+}
+
+func parseAuthMetadata(ctx context.Context, tokenManager auth.Manager) (*auth.Payload, error) {
+	authorization := metadataValue(ctx, authorizationMetadataKey)
+	if len(authorization) == 0 {
+		return nil, domain.ErrEmptyAuthHeader
+	}
+
+	fields := strings.Fields(authorization)
+	if len(fields) < 2 {
+		return nil, domain.ErrInvalidAuthHeaderFormat
+	}
+
+	authorizationType := fields[0]
+	if strings.ToLower(authorizationType) != authorizationTypeBearer {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnsupportedAuthType, authorizationType)
+	}
+
+	return tokenManager.VerifyToken(fields[1])
+}
+
+func getUserPayload(ctx context.Context) (*auth.Payload, error) {
+	payload, ok := ctx.Value(payloadKey{}).(*auth.Payload)
+	if !ok {
+		return nil, fmt.Errorf("user payload is missing")
+	}
+
+	return payload, nil
+}
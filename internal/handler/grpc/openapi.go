@@ -0,0 +1,14 @@
+package grpc
+
+import _ "embed" // the spec of the REST api.
+
+// openAPISpec is generated from the http rules of the protos with the
+// messages, it documents the REST api served through the gateway.
+//
+//go:embed pb/backend.swagger.json
+var openAPISpec []byte //nolint: gochecknoglobals
+
+// OpenAPISpec returns the swagger 2.0 spec of the REST api of the gateway.
+func OpenAPISpec() []byte {
+	return openAPISpec
+}
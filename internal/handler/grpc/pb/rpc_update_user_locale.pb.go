@@ -0,0 +1,198 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
+// source: rpc_update_user_locale.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type UpdateUserLocaleRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Locale string `protobuf:"bytes,1,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *UpdateUserLocaleRequest) Reset() {
+	*x = UpdateUserLocaleRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_update_user_locale_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateUserLocaleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserLocaleRequest) ProtoMessage() {}
+
+func (x *UpdateUserLocaleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_update_user_locale_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserLocaleRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserLocaleRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_update_user_locale_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *UpdateUserLocaleRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type UpdateUserLocaleResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *UpdateUserLocaleResponse) Reset() {
+	*x = UpdateUserLocaleResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_update_user_locale_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateUserLocaleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserLocaleResponse) ProtoMessage() {}
+
+func (x *UpdateUserLocaleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_update_user_locale_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserLocaleResponse.ProtoReflect.Descriptor instead.
+func (*UpdateUserLocaleResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_update_user_locale_proto_rawDescGZIP(), []int{1}
+}
+
+var File_rpc_update_user_locale_proto protoreflect.FileDescriptor
+
+var file_rpc_update_user_locale_proto_rawDesc = []byte{
+	0x0a, 0x1c, 0x72, 0x70, 0x63, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x75, 0x73, 0x65,
+	0x72, 0x5f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02,
+	0x70, 0x62, 0x22, 0x31, 0x0a, 0x17, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72,
+	0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c,
+	0x6f, 0x63, 0x61, 0x6c, 0x65, 0x22, 0x1a, 0x0a, 0x18, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55,
+	0x73, 0x65, 0x72, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x62, 0x30, 0x73, 0x68, 0x6b, 0x61, 0x2f, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x2f, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x72, 0x2f,
+	0x67, 0x72, 0x70, 0x63, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpc_update_user_locale_proto_rawDescOnce sync.Once
+	file_rpc_update_user_locale_proto_rawDescData = file_rpc_update_user_locale_proto_rawDesc
+)
+
+func file_rpc_update_user_locale_proto_rawDescGZIP() []byte {
+	file_rpc_update_user_locale_proto_rawDescOnce.Do(func() {
+		file_rpc_update_user_locale_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_update_user_locale_proto_rawDescData)
+	})
+	return file_rpc_update_user_locale_proto_rawDescData
+}
+
+var file_rpc_update_user_locale_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_update_user_locale_proto_goTypes = []interface{}{
+	(*UpdateUserLocaleRequest)(nil),  // 0: pb.UpdateUserLocaleRequest
+	(*UpdateUserLocaleResponse)(nil), // 1: pb.UpdateUserLocaleResponse
+}
+var file_rpc_update_user_locale_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_rpc_update_user_locale_proto_init() }
+func file_rpc_update_user_locale_proto_init() {
+	if File_rpc_update_user_locale_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_update_user_locale_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateUserLocaleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_update_user_locale_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateUserLocaleResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_update_user_locale_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_update_user_locale_proto_goTypes,
+		DependencyIndexes: file_rpc_update_user_locale_proto_depIdxs,
+		MessageInfos:      file_rpc_update_user_locale_proto_msgTypes,
+	}.Build()
+	File_rpc_update_user_locale_proto = out.File
+	file_rpc_update_user_locale_proto_rawDesc = nil
+	file_rpc_update_user_locale_proto_goTypes = nil
+	file_rpc_update_user_locale_proto_depIdxs = nil
+}
@@ -0,0 +1,65 @@
+package grpc
+
+import (
+	"context"
+	"net"
+
+	"github.com/b0shka/backend/pkg/clientinfo"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const userAgentMetadataKey = "user-agent"
+
+func metadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// clientIP is the address of the peer, the gRPC listener is not behind a
+// proxy. The calls of the gateway are made on behalf of the REST client.
+func clientIP(ctx context.Context) string {
+	if fromGateway(ctx) {
+		return metadataValue(ctx, MetadataClientIP)
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
+func userAgent(ctx context.Context) string {
+	if fromGateway(ctx) {
+		return metadataValue(ctx, MetadataUserAgent)
+	}
+
+	return metadataValue(ctx, userAgentMetadataKey)
+}
+
+// deviceID is the device the client identifies itself with, the gateway
+// passes the one of the REST request.
+func deviceID(ctx context.Context) string {
+	id := metadataValue(ctx, clientinfo.DeviceIDHeader)
+	if !clientinfo.ValidDeviceID(id) {
+		return ""
+	}
+
+	return id
+}
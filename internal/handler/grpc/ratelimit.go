@@ -0,0 +1,95 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/b0shka/backend/internal/config"
+	"github.com/b0shka/backend/internal/domain"
+	"github.com/b0shka/backend/internal/handler/grpc/pb"
+	"github.com/b0shka/backend/pkg/clientinfo"
+	"github.com/b0shka/backend/pkg/limiter"
+	"github.com/b0shka/backend/pkg/logger"
+	"google.golang.org/grpc"
+)
+
+// rateLimitPolicy is a policy under the name of the http route group it
+// limits, so that a client shares its budget between both apis.
+type rateLimitPolicy struct {
+	name   string
+	policy config.RateLimitPolicy
+}
+
+// rateLimitPolicies are the policies of the methods, in the order they are
+// applied.
+func rateLimitPolicies(cfg config.RateLimitConfig) map[string][]rateLimitPolicy {
+	auth := rateLimitPolicy{name: "auth", policy: cfg.Auth}
+	users := rateLimitPolicy{name: "users", policy: cfg.Users}
+
+	return map[string][]rateLimitPolicy{
+		pb.Backend_SendCode_FullMethodName:         {auth, {name: "send_code", policy: cfg.SendCode}},
+		pb.Backend_SignInUser_FullMethodName:       {auth},
+		pb.Backend_RefreshToken_FullMethodName:     {auth},
+		pb.Backend_GetUser_FullMethodName:          {users},
+		pb.Backend_UpdateUserLocale_FullMethodName: {users},
+		pb.Backend_DeleteUser_FullMethodName:       {users},
+	}
+}
+
+// rateLimitInterceptor applies the policies of the http api to the calls of
+// the gRPC clients. The calls of the gateway are limited by the http
+// middlewares already. The call is let through when the limiter fails, as
+// the http api does.
+func rateLimitInterceptor(l limiter.Limiter, cfg config.RateLimitConfig) grpc.UnaryServerInterceptor {
+	policies := rateLimitPolicies(cfg)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if l == nil || fromGateway(ctx) {
+			return handler(ctx, req)
+		}
+
+		for _, p := range policies[info.FullMethod] {
+			if p.policy.Requests <= 0 || p.policy.Period <= 0 {
+				continue
+			}
+
+			key := p.name + ":" + rateLimitKey(ctx, req, p.policy.Key)
+
+			res, err := l.Allow(ctx, key, limiter.Limit{Requests: p.policy.Requests, Period: p.policy.Period})
+			if err != nil {
+				logger.FromContext(ctx).Error("failed to apply rate limit", "policy", p.name, logger.Err(err))
+
+				continue
+			}
+
+			if !res.Allowed {
+				return nil, domain.NewThrottledError(domain.ErrRateLimitExceeded, res.RetryAfter)
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// rateLimitKey identifies the client of the call as the http api does,
+// falling back to the ip when the user or the email is not known.
+func rateLimitKey(ctx context.Context, req any, key string) string {
+	switch key {
+	case config.RateLimitKeyUser:
+		if payload, err := getUserPayload(ctx); err == nil {
+			return "user:" + payload.UserID.String()
+		}
+	case config.RateLimitKeyEmail:
+		if req, ok := req.(interface{ GetEmail() string }); ok {
+			if email := strings.ToLower(strings.TrimSpace(req.GetEmail())); email != "" {
+				sum := sha256.Sum256([]byte(email))
+
+				return "email:" + hex.EncodeToString(sum[:])
+			}
+		}
+	}
+
+	return "ip:" + clientinfo.FromContext(ctx).IP
+}
@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/b0shka/backend/internal/domain"
+	"github.com/b0shka/backend/internal/handler/grpc/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (h *Handler) GetUser(ctx context.Context, _ *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	payload, err := getUserPayload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := h.services.Users.GetByID(ctx, payload.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetUserResponse{
+		User: &pb.User{
+			Id:        user.ID.String(),
+			Email:     user.Email,
+			Locale:    user.Locale,
+			CreatedAt: timestamppb.New(user.CreatedAt),
+		},
+	}, nil
+}
+
+func (h *Handler) UpdateUserLocale(
+	ctx context.Context,
+	req *pb.UpdateUserLocaleRequest,
+) (*pb.UpdateUserLocaleResponse, error) {
+	payload, err := getUserPayload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetLocale() == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	if err = h.services.Users.UpdateLocale(ctx, payload.UserID, req.GetLocale()); err != nil {
+		return nil, err
+	}
+
+	return &pb.UpdateUserLocaleResponse{}, nil
+}
+
+func (h *Handler) DeleteUser(ctx context.Context, _ *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
+	payload, err := getUserPayload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = h.services.Users.Delete(ctx, payload.UserID); err != nil {
+		return nil, err
+	}
+
+	return &pb.DeleteUserResponse{}, nil
+}
@@ -0,0 +1,14 @@
+package http
+
+import (
+	"github.com/b0shka/backend/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) initAdminRoutes(api *gin.RouterGroup, apiKey string, policy config.RateLimitPolicy) {
+	admin := api.Group("/admin", h.rateLimit("admin", policy), adminIdentity(apiKey))
+	{
+		h.initWebhooksRoutes(admin)
+		h.initTasksRoutes(admin)
+	}
+}
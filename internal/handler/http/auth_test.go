@@ -4,16 +4,16 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/b0shka/backend/internal/config"
 	"github.com/b0shka/backend/internal/domain"
 	domain_auth "github.com/b0shka/backend/internal/domain/auth"
 	"github.com/b0shka/backend/internal/service"
 	mock_service "github.com/b0shka/backend/internal/service/mocks"
-	"github.com/b0shka/backend/pkg/logger"
 	"github.com/b0shka/backend/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
@@ -26,7 +26,6 @@ var ErrInternalServerError = errors.New("test: internal server error")
 func requireBodyMatchTokens(t *testing.T, body *bytes.Buffer, token domain_auth.SignInOutput) {
 	var gotTokens domain_auth.SignInOutput
 	err := json.Unmarshal(body.Bytes(), &gotTokens)
-	logger.Info(gotTokens)
 
 	require.NoError(t, err)
 	require.Equal(t, token.AccessToken, gotTokens.AccessToken)
@@ -58,7 +57,8 @@ func TestHandler_sendCodeEmail(t *testing.T) {
 				"email": "email@ya.ru",
 			},
 			userInput: domain_auth.SendCodeEmailInput{
-				Email: "email@ya.ru",
+				Email:    "email@ya.ru",
+				ClientIP: "192.0.2.1",
 			},
 			mockBehavior: func(s *mock_service.MockAuth, input domain_auth.SendCodeEmailInput) {
 				s.EXPECT().SendCodeEmail(gomock.Any(), input).Return(nil)
@@ -66,6 +66,18 @@ func TestHandler_sendCodeEmail(t *testing.T) {
 			statusCode:   200,
 			responseBody: "",
 		},
+		{
+			name: "cooldown",
+			body: gin.H{
+				"email": "email@ya.ru",
+			},
+			mockBehavior: func(s *mock_service.MockAuth, input domain_auth.SendCodeEmailInput) {
+				s.EXPECT().SendCodeEmail(gomock.Any(), gomock.Any()).
+					Return(domain.NewThrottledError(domain.ErrSendCodeCooldown, 41500*time.Millisecond))
+			},
+			statusCode:   429,
+			responseBody: problemBody(t, domain.NewThrottledError(domain.ErrSendCodeCooldown, 41500*time.Millisecond)),
+		},
 		{
 			name: "error send code",
 			body: gin.H{
@@ -76,7 +88,7 @@ func TestHandler_sendCodeEmail(t *testing.T) {
 					Return(ErrInternalServerError)
 			},
 			statusCode:   500,
-			responseBody: fmt.Sprintf(`{"message":"%s"}`, ErrInternalServerError),
+			responseBody: problemBody(t, ErrInternalServerError),
 		},
 		{
 			name: "empty fields",
@@ -85,7 +97,7 @@ func TestHandler_sendCodeEmail(t *testing.T) {
 			},
 			mockBehavior: func(s *mock_service.MockAuth, input domain_auth.SendCodeEmailInput) {},
 			statusCode:   400,
-			responseBody: `{"message":"invalid input body"}`,
+			responseBody: problemBody(t, domain.ErrInvalidInput),
 		},
 		{
 			name: "invalid email",
@@ -94,7 +106,7 @@ func TestHandler_sendCodeEmail(t *testing.T) {
 			},
 			mockBehavior: func(s *mock_service.MockAuth, input domain_auth.SendCodeEmailInput) {},
 			statusCode:   400,
-			responseBody: `{"message":"invalid input body"}`,
+			responseBody: problemBody(t, domain.ErrInvalidInput),
 		},
 	}
 
@@ -107,10 +119,10 @@ func TestHandler_sendCodeEmail(t *testing.T) {
 			testCase.mockBehavior(authService, testCase.userInput)
 
 			services := &service.Services{Auth: authService}
-			handler := Handler{services: services}
+			handler := newTestGatewayHandler(t, services, nil)
 
-			router := gin.Default()
-			router.POST("/send-code", handler.sendCodeEmail)
+			router := newTestRouter()
+			handler.initAuthRoutes(router.Group("/api/v1"), config.RateLimitConfig{}, nil)
 
 			recorder := httptest.NewRecorder()
 
@@ -119,7 +131,7 @@ func TestHandler_sendCodeEmail(t *testing.T) {
 
 			req := httptest.NewRequest(
 				http.MethodPost,
-				"/send-code",
+				"/api/v1/auth/send-code",
 				bytes.NewReader(data),
 			)
 
@@ -127,6 +139,10 @@ func TestHandler_sendCodeEmail(t *testing.T) {
 
 			require.Equal(t, testCase.statusCode, recorder.Code)
 			require.Equal(t, testCase.responseBody, recorder.Body.String())
+
+			if testCase.statusCode == http.StatusTooManyRequests {
+				require.Equal(t, "42", recorder.Header().Get("Retry-After"))
+			}
 		})
 	}
 }
@@ -187,7 +203,7 @@ func TestHandler_userSignIn(t *testing.T) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 				require.Equal(
 					t,
-					fmt.Sprintf(`{"message":"%s"}`, ErrInternalServerError),
+					problemBody(t, ErrInternalServerError),
 					recorder.Body.String(),
 				)
 			},
@@ -207,7 +223,7 @@ func TestHandler_userSignIn(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 				require.Equal(
 					t,
-					fmt.Sprintf(`{"message":"%s"}`, domain.ErrSecretCodeInvalid),
+					problemBody(t, domain.ErrSecretCodeInvalid),
 					recorder.Body.String(),
 				)
 			},
@@ -227,7 +243,7 @@ func TestHandler_userSignIn(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 				require.Equal(
 					t,
-					fmt.Sprintf(`{"message":"%s"}`, domain.ErrSecretCodeExpired),
+					problemBody(t, domain.ErrSecretCodeExpired),
 					recorder.Body.String(),
 				)
 			},
@@ -243,7 +259,7 @@ func TestHandler_userSignIn(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 				require.Equal(
 					t,
-					`{"message":"invalid input body"}`,
+					problemBody(t, domain.ErrInvalidInput),
 					recorder.Body.String(),
 				)
 			},
@@ -259,7 +275,7 @@ func TestHandler_userSignIn(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 				require.Equal(
 					t,
-					`{"message":"invalid input body"}`,
+					problemBody(t, domain.ErrInvalidInput),
 					recorder.Body.String(),
 				)
 			},
@@ -275,7 +291,7 @@ func TestHandler_userSignIn(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 				require.Equal(
 					t,
-					`{"message":"invalid input body"}`,
+					problemBody(t, domain.ErrInvalidInput),
 					recorder.Body.String(),
 				)
 			},
@@ -291,7 +307,7 @@ func TestHandler_userSignIn(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 				require.Equal(
 					t,
-					`{"message":"invalid input body"}`,
+					problemBody(t, domain.ErrInvalidInput),
 					recorder.Body.String(),
 				)
 			},
@@ -307,7 +323,7 @@ func TestHandler_userSignIn(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 				require.Equal(
 					t,
-					`{"message":"invalid input body"}`,
+					problemBody(t, domain.ErrInvalidInput),
 					recorder.Body.String(),
 				)
 			},
@@ -323,10 +339,10 @@ func TestHandler_userSignIn(t *testing.T) {
 			testCase.mockBehavior(authService, testCase.userInput)
 
 			services := &service.Services{Auth: authService}
-			handler := Handler{services: services}
+			handler := newTestGatewayHandler(t, services, nil)
 
-			router := gin.Default()
-			router.POST("/sign-in", handler.signIn)
+			router := newTestRouter()
+			handler.initAuthRoutes(router.Group("/api/v1"), config.RateLimitConfig{}, nil)
 
 			data, err := json.Marshal(testCase.body)
 			require.NoError(t, err)
@@ -334,7 +350,7 @@ func TestHandler_userSignIn(t *testing.T) {
 			recorder := httptest.NewRecorder()
 			req := httptest.NewRequest(
 				http.MethodPost,
-				"/sign-in",
+				"/api/v1/auth/sign-in",
 				bytes.NewReader(data),
 			)
 
@@ -396,7 +412,7 @@ func TestHandler_refreshToken(t *testing.T) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 				require.Equal(
 					t,
-					fmt.Sprintf(`{"message":"%s"}`, ErrInternalServerError),
+					problemBody(t, ErrInternalServerError),
 					recorder.Body.String(),
 				)
 			},
@@ -418,7 +434,7 @@ func TestHandler_refreshToken(t *testing.T) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
 				require.Equal(
 					t,
-					fmt.Sprintf(`{"message":"%s"}`, domain.ErrSessionNotFound),
+					problemBody(t, domain.ErrSessionNotFound),
 					recorder.Body.String(),
 				)
 			},
@@ -440,7 +456,7 @@ func TestHandler_refreshToken(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 				require.Equal(
 					t,
-					fmt.Sprintf(`{"message":"%s"}`, domain.ErrSessionBlocked),
+					problemBody(t, domain.ErrSessionBlocked),
 					recorder.Body.String(),
 				)
 			},
@@ -462,7 +478,7 @@ func TestHandler_refreshToken(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 				require.Equal(
 					t,
-					fmt.Sprintf(`{"message":"%s"}`, domain.ErrIncorrectSessionUser),
+					problemBody(t, domain.ErrIncorrectSessionUser),
 					recorder.Body.String(),
 				)
 			},
@@ -484,7 +500,7 @@ func TestHandler_refreshToken(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 				require.Equal(
 					t,
-					fmt.Sprintf(`{"message":"%s"}`, domain.ErrMismatchedSession),
+					problemBody(t, domain.ErrMismatchedSession),
 					recorder.Body.String(),
 				)
 			},
@@ -506,7 +522,7 @@ func TestHandler_refreshToken(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 				require.Equal(
 					t,
-					fmt.Sprintf(`{"message":"%s"}`, domain.ErrExpiredToken),
+					problemBody(t, domain.ErrExpiredToken),
 					recorder.Body.String(),
 				)
 			},
@@ -528,7 +544,7 @@ func TestHandler_refreshToken(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 				require.Equal(
 					t,
-					fmt.Sprintf(`{"message":"%s"}`, domain.ErrInvalidToken),
+					problemBody(t, domain.ErrInvalidToken),
 					recorder.Body.String(),
 				)
 			},
@@ -543,7 +559,7 @@ func TestHandler_refreshToken(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 				require.Equal(
 					t,
-					`{"message":"invalid input body"}`,
+					problemBody(t, domain.ErrInvalidInput),
 					recorder.Body.String(),
 				)
 			},
@@ -559,10 +575,10 @@ func TestHandler_refreshToken(t *testing.T) {
 			testCase.mockBehavior(authService, testCase.userInput)
 
 			services := &service.Services{Auth: authService}
-			handler := Handler{services: services}
+			handler := newTestGatewayHandler(t, services, nil)
 
-			router := gin.Default()
-			router.POST("/refresh", handler.refreshToken)
+			router := newTestRouter()
+			handler.initAuthRoutes(router.Group("/api/v1"), config.RateLimitConfig{}, nil)
 
 			data, err := json.Marshal(testCase.body)
 			require.NoError(t, err)
@@ -570,7 +586,7 @@ func TestHandler_refreshToken(t *testing.T) {
 			recorder := httptest.NewRecorder()
 			req := httptest.NewRequest(
 				http.MethodPost,
-				"/refresh",
+				"/api/v1/auth/refresh",
 				bytes.NewReader(data),
 			)
 
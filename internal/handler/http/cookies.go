@@ -0,0 +1,202 @@
+package http
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/b0shka/backend/internal/config"
+	"github.com/b0shka/backend/internal/domain"
+	"github.com/b0shka/backend/internal/handler/grpc/pb"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/proto"
+)
+
+// The __Host- prefix makes the browsers accept the cookies only when they
+// are Secure, set by the host itself and for the whole site.
+const (
+	accessTokenCookie  = "__Host-access_token"
+	refreshTokenCookie = "__Host-refresh_token"
+	csrfTokenCookie    = "__Host-csrf_token"
+
+	csrfTokenHeaderKey = "X-CSRF-Token"
+	csrfTokenBytes     = 32
+)
+
+// sessionCookies keeps the tokens of the browser clients in HttpOnly cookies,
+// the scripts of the page can not read them. The requests authenticated by
+// the cookies are protected from CSRF with a double-submit token: the csrf
+// cookie is readable by the page, that sends it back in the header.
+type sessionCookies struct {
+	sameSite        http.SameSite
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+}
+
+// newSessionCookies returns nil when the cookie mode is off.
+func newSessionCookies(cfg config.AuthConfig) *sessionCookies {
+	if !cfg.Cookies.Enabled {
+		return nil
+	}
+
+	sameSite := http.SameSiteStrictMode
+
+	switch strings.ToLower(cfg.Cookies.SameSite) {
+	case "lax":
+		sameSite = http.SameSiteLaxMode
+	case "none":
+		sameSite = http.SameSiteNoneMode
+	}
+
+	return &sessionCookies{
+		sameSite:        sameSite,
+		accessTokenTTL:  cfg.JWT.AccessTokenTTL,
+		refreshTokenTTL: cfg.JWT.RefreshTokenTTL,
+	}
+}
+
+// setTokens sets the cookies from the tokens of the sign in and the refresh
+// calls once the gateway has the result. The tokens stay in the body for the
+// other clients.
+func (s *sessionCookies) setTokens(c *gin.Context) {
+	c.Set(gatewayResponseHookCtx, gatewayResponseHook(func(c *gin.Context, msg proto.Message) error {
+		switch res := msg.(type) {
+		case *pb.SignInUserResponse:
+			s.setCookie(c, accessTokenCookie, res.GetAccessToken(), s.accessTokenTTL, true)
+			s.setCookie(c, refreshTokenCookie, res.GetRefreshToken(), s.refreshTokenTTL, true)
+		case *pb.RefreshTokenResponse:
+			s.setCookie(c, accessTokenCookie, res.GetAccessToken(), s.accessTokenTTL, true)
+		default:
+			return nil
+		}
+
+		csrfToken, err := newCSRFToken()
+		if err != nil {
+			return err
+		}
+
+		s.setCookie(c, csrfTokenCookie, csrfToken, s.refreshTokenTTL, false)
+
+		return nil
+	}))
+}
+
+// refreshToken passes the refresh token of the cookie to the refresh call
+// when the body has none.
+func (s *sessionCookies) refreshToken(c *gin.Context) {
+	token, err := c.Cookie(refreshTokenCookie)
+	if err != nil || token == "" {
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		newInputErrorResponse(c, err)
+
+		return
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if err := verifyCSRFToken(c); err != nil {
+			newErrorResponse(c, err)
+
+			return
+		}
+
+		if body, err = json.Marshal(map[string]string{"refresh_token": token}); err != nil {
+			newErrorResponse(c, err)
+
+			return
+		}
+	}
+
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	c.Request.ContentLength = int64(len(body))
+}
+
+// @Summary		Logout
+// @Tags			auth
+// @Description	clear the session cookies of the browser clients
+// @ModuleID		logout
+// @Accept			json
+// @Produce		json
+// @Param			X-CSRF-Token	header		string	false	"csrf token, required with the session cookies"
+// @Success		200				{string}	string	"ok"
+// @Failure		403				{object}	problem
+// @Failure		500				{object}	problem
+// @Failure		default			{object}	problem
+// @Router			/auth/logout [post]
+func (s *sessionCookies) logout(c *gin.Context) {
+	if hasSessionCookie(c) {
+		if err := verifyCSRFToken(c); err != nil {
+			newErrorResponse(c, err)
+
+			return
+		}
+	}
+
+	for _, name := range []string{accessTokenCookie, refreshTokenCookie, csrfTokenCookie} {
+		s.setCookie(c, name, "", -time.Second, name != csrfTokenCookie)
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (s *sessionCookies) setCookie(c *gin.Context, name, value string, ttl time.Duration, httpOnly bool) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   int(ttl.Seconds()),
+		Secure:   true,
+		HttpOnly: httpOnly,
+		SameSite: s.sameSite,
+	})
+}
+
+func hasSessionCookie(c *gin.Context) bool {
+	for _, name := range []string{accessTokenCookie, refreshTokenCookie} {
+		if token, err := c.Cookie(name); err == nil && token != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// verifyCSRFToken lets through the safe methods and the requests that send
+// the csrf cookie back in the header, another site can neither read the
+// cookie nor set the header.
+func verifyCSRFToken(c *gin.Context) error {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return nil
+	}
+
+	cookie, err := c.Cookie(csrfTokenCookie)
+	if err != nil || cookie == "" {
+		return domain.ErrInvalidCSRFToken
+	}
+
+	header := c.GetHeader(csrfTokenHeaderKey)
+	if subtle.ConstantTimeCompare([]byte(header), []byte(cookie)) != 1 {
+		return domain.ErrInvalidCSRFToken
+	}
+
+	return nil
+}
+
+func newCSRFToken() (string, error) {
+	token := make([]byte, csrfTokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
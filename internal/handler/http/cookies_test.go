@@ -0,0 +1,314 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/b0shka/backend/internal/config"
+	"github.com/b0shka/backend/internal/domain"
+	domain_auth "github.com/b0shka/backend/internal/domain/auth"
+	"github.com/b0shka/backend/internal/service"
+	mock_service "github.com/b0shka/backend/internal/service/mocks"
+	"github.com/b0shka/backend/pkg/auth"
+	"github.com/b0shka/backend/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSessionCookies() *sessionCookies {
+	return newSessionCookies(config.AuthConfig{
+		JWT: config.JWTConfig{
+			AccessTokenTTL:  time.Minute * 15,
+			RefreshTokenTTL: time.Hour,
+		},
+		Cookies: config.CookiesConfig{Enabled: true},
+	})
+}
+
+func responseCookies(recorder *httptest.ResponseRecorder) map[string]*http.Cookie {
+	cookies := make(map[string]*http.Cookie)
+	for _, cookie := range recorder.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+
+	return cookies
+}
+
+func TestNewSessionCookies(t *testing.T) {
+	require.Nil(t, newSessionCookies(config.AuthConfig{}))
+
+	tests := map[string]http.SameSite{
+		"":       http.SameSiteStrictMode,
+		"strict": http.SameSiteStrictMode,
+		"Lax":    http.SameSiteLaxMode,
+		"none":   http.SameSiteNoneMode,
+	}
+
+	for sameSite, mode := range tests {
+		cookies := newSessionCookies(config.AuthConfig{Cookies: config.CookiesConfig{Enabled: true, SameSite: sameSite}})
+		require.Equal(t, mode, cookies.sameSite)
+	}
+}
+
+func TestHandler_signInCookies(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	authService := mock_service.NewMockAuth(mockCtl)
+	authService.EXPECT().SignIn(gomock.Any(), gomock.Any()).Return(domain_auth.SignInOutput{
+		SessionID:    uuid.New(),
+		RefreshToken: "refresh",
+		AccessToken:  "access",
+	}, nil)
+
+	handler := newTestGatewayHandler(t, &service.Services{Auth: authService}, nil)
+
+	router := newTestRouter()
+	handler.initAuthRoutes(router.Group("/api/v1"), config.RateLimitConfig{}, newTestSessionCookies())
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/api/v1/auth/sign-in",
+		bytes.NewBufferString(`{"email":"email@ya.ru","secret_code":"123456"}`),
+	)
+
+	router.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `"refresh_token":"refresh"`)
+
+	cookies := responseCookies(recorder)
+
+	require.Equal(t, "access", cookies[accessTokenCookie].Value)
+	require.Equal(t, 900, cookies[accessTokenCookie].MaxAge)
+	require.Equal(t, "refresh", cookies[refreshTokenCookie].Value)
+	require.Equal(t, 3600, cookies[refreshTokenCookie].MaxAge)
+
+	for _, name := range []string{accessTokenCookie, refreshTokenCookie} {
+		require.True(t, cookies[name].HttpOnly)
+		require.True(t, cookies[name].Secure)
+		require.Equal(t, "/", cookies[name].Path)
+		require.Equal(t, http.SameSiteStrictMode, cookies[name].SameSite)
+	}
+
+	require.NotEmpty(t, cookies[csrfTokenCookie].Value)
+	require.False(t, cookies[csrfTokenCookie].HttpOnly)
+	require.True(t, cookies[csrfTokenCookie].Secure)
+}
+
+func TestHandler_refreshTokenCookies(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		csrfToken    string
+		mockBehavior func(s *mock_service.MockAuth)
+		statusCode   int
+	}{
+		{
+			name:      "ok",
+			csrfToken: "csrf",
+			mockBehavior: func(s *mock_service.MockAuth) {
+				s.EXPECT().
+					RefreshToken(gomock.Any(), domain_auth.NewRefreshTokenInput("refresh")).
+					Return(domain_auth.RefreshTokenOutput{AccessToken: "access"}, nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "token in body",
+			body: `{"refresh_token":"body"}`,
+			mockBehavior: func(s *mock_service.MockAuth) {
+				s.EXPECT().
+					RefreshToken(gomock.Any(), domain_auth.NewRefreshTokenInput("body")).
+					Return(domain_auth.RefreshTokenOutput{AccessToken: "access"}, nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:         "no csrf token",
+			mockBehavior: func(s *mock_service.MockAuth) {},
+			statusCode:   http.StatusForbidden,
+		},
+		{
+			name:         "wrong csrf token",
+			csrfToken:    "wrong",
+			mockBehavior: func(s *mock_service.MockAuth) {},
+			statusCode:   http.StatusForbidden,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			defer mockCtl.Finish()
+
+			authService := mock_service.NewMockAuth(mockCtl)
+			testCase.mockBehavior(authService)
+
+			handler := newTestGatewayHandler(t, &service.Services{Auth: authService}, nil)
+
+			router := newTestRouter()
+			handler.initAuthRoutes(router.Group("/api/v1"), config.RateLimitConfig{}, newTestSessionCookies())
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/refresh", bytes.NewBufferString(testCase.body))
+			req.AddCookie(&http.Cookie{Name: refreshTokenCookie, Value: "refresh"})
+			req.AddCookie(&http.Cookie{Name: csrfTokenCookie, Value: "csrf"})
+
+			if testCase.csrfToken != "" {
+				req.Header.Set(csrfTokenHeaderKey, testCase.csrfToken)
+			}
+
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, testCase.statusCode, recorder.Code)
+
+			if testCase.statusCode == http.StatusOK {
+				require.Equal(t, "access", responseCookies(recorder)[accessTokenCookie].Value)
+			} else {
+				require.Equal(t, problemBody(t, domain.ErrInvalidCSRFToken), recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_userIdentityCookies(t *testing.T) {
+	symmetricKey, err := utils.RandomString(32)
+	require.NoError(t, err)
+
+	tokenManager, err := auth.NewPasetoManager(symmetricKey)
+	require.NoError(t, err)
+
+	token, _, err := tokenManager.CreateToken(uuid.New(), time.Minute)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		method       string
+		cookies      *sessionCookies
+		csrfToken    string
+		statusCode   int
+		responseBody string
+	}{
+		{
+			name:       "safe method",
+			method:     http.MethodGet,
+			cookies:    newTestSessionCookies(),
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "csrf token",
+			method:     http.MethodDelete,
+			cookies:    newTestSessionCookies(),
+			csrfToken:  "csrf",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:         "no csrf token",
+			method:       http.MethodDelete,
+			cookies:      newTestSessionCookies(),
+			statusCode:   http.StatusForbidden,
+			responseBody: problemBody(t, domain.ErrInvalidCSRFToken),
+		},
+		{
+			name:         "cookie mode off",
+			method:       http.MethodGet,
+			statusCode:   http.StatusUnauthorized,
+			responseBody: problemBody(t, domain.ErrEmptyAuthHeader),
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			router := newTestRouter()
+			router.Handle(testCase.method, "/identity", userIdentity(tokenManager, testCase.cookies), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(testCase.method, "/identity", nil)
+			req.AddCookie(&http.Cookie{Name: accessTokenCookie, Value: token})
+			req.AddCookie(&http.Cookie{Name: csrfTokenCookie, Value: "csrf"})
+
+			if testCase.csrfToken != "" {
+				req.Header.Set(csrfTokenHeaderKey, testCase.csrfToken)
+			}
+
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, testCase.statusCode, recorder.Code)
+			require.Equal(t, testCase.responseBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHandler_logout(t *testing.T) {
+	handler := &Handler{}
+
+	router := newTestRouter()
+	handler.initAuthRoutes(router.Group("/api/v1"), config.RateLimitConfig{}, newTestSessionCookies())
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/logout", nil)
+	req.AddCookie(&http.Cookie{Name: refreshTokenCookie, Value: "refresh"})
+
+	router.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+
+	req.AddCookie(&http.Cookie{Name: csrfTokenCookie, Value: "csrf"})
+	req.Header.Set(csrfTokenHeaderKey, "csrf")
+
+	router.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	cookies := responseCookies(recorder)
+	for _, name := range []string{accessTokenCookie, refreshTokenCookie, csrfTokenCookie} {
+		require.Empty(t, cookies[name].Value)
+		require.Negative(t, cookies[name].MaxAge)
+	}
+}
+
+func TestHandler_userIdentityCookiesGateway(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	symmetricKey, err := utils.RandomString(32)
+	require.NoError(t, err)
+
+	tokenManager, err := auth.NewPasetoManager(symmetricKey)
+	require.NoError(t, err)
+
+	userID := uuid.New()
+
+	token, _, err := tokenManager.CreateToken(userID, time.Minute)
+	require.NoError(t, err)
+
+	usersService := mock_service.NewMockUsers(mockCtl)
+	usersService.EXPECT().UpdateLocale(gomock.Any(), userID, "ru").Return(nil)
+
+	handler := newTestGatewayHandler(t, &service.Services{Users: usersService}, tokenManager)
+
+	router := newTestRouter()
+	handler.initUsersRoutes(router.Group("/api/v1"), config.RateLimitPolicy{}, newTestSessionCookies())
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/locale", bytes.NewBufferString(`{"locale":"ru"}`))
+	req.AddCookie(&http.Cookie{Name: accessTokenCookie, Value: token})
+	req.AddCookie(&http.Cookie{Name: csrfTokenCookie, Value: "csrf"})
+	req.Header.Set(csrfTokenHeaderKey, "csrf")
+
+	router.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Empty(t, recorder.Body.String())
+}
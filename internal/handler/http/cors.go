@@ -0,0 +1,162 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/b0shka/backend/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+const corsAnyOrigin = "*"
+
+// corsPolicy answers the browser clients of the allowed origins only. An
+// origin such as "https://*.example.com" allows every subdomain of the site
+// with the scheme, "*" allows every origin.
+type corsPolicy struct {
+	anyOrigin        bool
+	origins          map[string]struct{}
+	wildcards        []corsWildcard
+	allowCredentials bool
+	allowMethods     string
+	allowHeaders     string
+	exposeHeaders    string
+	maxAge           string
+}
+
+type corsWildcard struct {
+	prefix string
+	suffix string
+}
+
+// newCORSPolicy fails on the policies the browsers would refuse, the
+// credentials can not be allowed for every origin.
+func newCORSPolicy(cfg config.CORSConfig) (*corsPolicy, error) {
+	policy := &corsPolicy{
+		origins:          make(map[string]struct{}, len(cfg.AllowedOrigins)),
+		allowCredentials: cfg.AllowCredentials,
+		allowMethods:     strings.Join(cfg.AllowedMethods, ", "),
+		allowHeaders:     strings.Join(cfg.AllowedHeaders, ", "),
+		exposeHeaders:    strings.Join(cfg.ExposedHeaders, ", "),
+	}
+
+	if cfg.MaxAge > 0 {
+		policy.maxAge = strconv.Itoa(int(cfg.MaxAge.Seconds()))
+	}
+
+	for _, origin := range cfg.AllowedOrigins {
+		origin = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+
+		switch {
+		case origin == "":
+		case origin == corsAnyOrigin:
+			policy.anyOrigin = true
+		case strings.Contains(origin, "*"):
+			scheme, host, found := strings.Cut(origin, "://*.")
+			if !found || host == "" || strings.Contains(host, "*") {
+				return nil, fmt.Errorf("invalid cors origin: %s", origin)
+			}
+
+			policy.wildcards = append(policy.wildcards, corsWildcard{prefix: scheme + "://", suffix: "." + host})
+		default:
+			policy.origins[origin] = struct{}{}
+		}
+	}
+
+	if policy.anyOrigin && policy.allowCredentials {
+		return nil, errors.New("cors credentials can not be allowed for any origin")
+	}
+
+	return policy, nil
+}
+
+func (p *corsPolicy) allowOrigin(origin string) bool {
+	if p.anyOrigin {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+
+	if _, ok := p.origins[origin]; ok {
+		return true
+	}
+
+	for _, wildcard := range p.wildcards {
+		if len(origin) > len(wildcard.prefix)+len(wildcard.suffix) &&
+			strings.HasPrefix(origin, wildcard.prefix) && strings.HasSuffix(origin, wildcard.suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// middleware sets the CORS headers of the allowed origins and answers their
+// preflight requests. The requests of the other origins are served without
+// the headers, the browsers do not let the pages read the responses.
+func (p *corsPolicy) middleware(c *gin.Context) {
+	origin := c.GetHeader("Origin")
+	if origin == "" {
+		c.Next()
+
+		return
+	}
+
+	if !p.anyOrigin || p.allowCredentials {
+		c.Writer.Header().Add("Vary", "Origin")
+	}
+
+	preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+	if !p.allowOrigin(origin) {
+		if preflight {
+			c.AbortWithStatus(http.StatusForbidden)
+
+			return
+		}
+
+		c.Next()
+
+		return
+	}
+
+	if p.anyOrigin {
+		c.Header("Access-Control-Allow-Origin", corsAnyOrigin)
+	} else {
+		c.Header("Access-Control-Allow-Origin", origin)
+	}
+
+	if p.allowCredentials {
+		c.Header("Access-Control-Allow-Credentials", "true")
+	}
+
+	if !preflight {
+		if p.exposeHeaders != "" {
+			c.Header("Access-Control-Expose-Headers", p.exposeHeaders)
+		}
+
+		c.Next()
+
+		return
+	}
+
+	c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+	c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+
+	if p.allowMethods != "" {
+		c.Header("Access-Control-Allow-Methods", p.allowMethods)
+	}
+
+	if p.allowHeaders != "" {
+		c.Header("Access-Control-Allow-Headers", p.allowHeaders)
+	}
+
+	if p.maxAge != "" {
+		c.Header("Access-Control-Max-Age", p.maxAge)
+	}
+
+	c.AbortWithStatus(http.StatusNoContent)
+}
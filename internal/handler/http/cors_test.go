@@ -0,0 +1,161 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/b0shka/backend/internal/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCORSPolicy(t *testing.T) {
+	_, err := newCORSPolicy(config.CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+	require.Error(t, err)
+
+	_, err = newCORSPolicy(config.CORSConfig{AllowedOrigins: []string{"https://app.*.com"}})
+	require.Error(t, err)
+
+	policy, err := newCORSPolicy(config.CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com/", "https://*.example.org", " "},
+	})
+	require.NoError(t, err)
+
+	tests := map[string]bool{
+		"https://app.example.com":      true,
+		"https://APP.example.com":      true,
+		"http://app.example.com":       false,
+		"https://example.com":          false,
+		"https://a.example.org":        true,
+		"https://a.b.example.org":      true,
+		"https://example.org":          false,
+		"https://.example.org":         false,
+		"http://a.example.org":         false,
+		"https://a.example.org.evil.a": false,
+	}
+
+	for origin, allowed := range tests {
+		require.Equal(t, allowed, policy.allowOrigin(origin), origin)
+	}
+}
+
+func TestCORSPolicy_middleware(t *testing.T) {
+	cfg := config.CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           time.Minute * 10,
+	}
+
+	tests := []struct {
+		name       string
+		cfg        config.CORSConfig
+		method     string
+		headers    map[string]string
+		statusCode int
+		expected   map[string]string
+	}{
+		{
+			name:       "no origin",
+			cfg:        cfg,
+			method:     http.MethodGet,
+			statusCode: http.StatusOK,
+			expected:   map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:       "allowed origin",
+			cfg:        cfg,
+			method:     http.MethodGet,
+			headers:    map[string]string{"Origin": "https://app.example.com"},
+			statusCode: http.StatusOK,
+			expected: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "X-Request-ID",
+				"Access-Control-Allow-Methods":     "",
+				"Vary":                             "Origin",
+				"Content-Type":                     "text/plain; charset=utf-8",
+			},
+		},
+		{
+			name:       "denied origin",
+			cfg:        cfg,
+			method:     http.MethodGet,
+			headers:    map[string]string{"Origin": "https://evil.com"},
+			statusCode: http.StatusOK,
+			expected:   map[string]string{"Access-Control-Allow-Origin": "", "Vary": "Origin"},
+		},
+		{
+			name:   "preflight",
+			cfg:    cfg,
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://app.example.com",
+				"Access-Control-Request-Method": http.MethodPost,
+			},
+			statusCode: http.StatusNoContent,
+			expected: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Allow-Methods":     "GET, POST",
+				"Access-Control-Allow-Headers":     "Authorization, Content-Type",
+				"Access-Control-Max-Age":           "600",
+			},
+		},
+		{
+			name:   "preflight of denied origin",
+			cfg:    cfg,
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://evil.com",
+				"Access-Control-Request-Method": http.MethodPost,
+			},
+			statusCode: http.StatusForbidden,
+			expected:   map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:       "any origin",
+			cfg:        config.CORSConfig{AllowedOrigins: []string{"*"}},
+			method:     http.MethodGet,
+			headers:    map[string]string{"Origin": "https://app.example.com"},
+			statusCode: http.StatusOK,
+			expected: map[string]string{
+				"Access-Control-Allow-Origin":      "*",
+				"Access-Control-Allow-Credentials": "",
+				"Vary":                             "",
+			},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			policy, err := newCORSPolicy(testCase.cfg)
+			require.NoError(t, err)
+
+			router := newTestRouter()
+			router.Use(policy.middleware)
+			router.Handle(testCase.method, "/cors", func(c *gin.Context) {
+				c.String(http.StatusOK, "ok")
+			})
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(testCase.method, "/cors", nil)
+
+			for key, value := range testCase.headers {
+				req.Header.Set(key, value)
+			}
+
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, testCase.statusCode, recorder.Code)
+
+			for key, value := range testCase.expected {
+				require.Equal(t, value, recorder.Header().Get(key), key)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/b0shka/backend/internal/domain"
+	grpchandler "github.com/b0shka/backend/internal/handler/grpc"
+	"github.com/b0shka/backend/internal/handler/grpc/pb"
+	"github.com/b0shka/backend/pkg/clientinfo"
+	"github.com/b0shka/backend/pkg/i18n"
+	"github.com/b0shka/backend/pkg/logger"
+	"github.com/b0shka/backend/pkg/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+type ginContextKey struct{}
+
+const (
+	gatewayResponseHookCtx = "gatewayResponseHook"
+	gatewayBodyCtx         = "gatewayBody"
+)
+
+// gatewayResponseHook sees the result of the call before it is written, the
+// response headers can still be set.
+type gatewayResponseHook func(c *gin.Context, msg proto.Message) error
+
+// NewGateway translates the REST calls of the http rules of the protos to the
+// gRPC calls on the connection. The routes of the api are served by gin all
+// the same, so that the middlewares and the error responses stay as they are.
+func NewGateway(conn grpc.ClientConnInterface) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &gatewayMarshaler{
+			JSONPb: &runtime.JSONPb{
+				MarshalOptions: protojson.MarshalOptions{
+					UseProtoNames:   true,
+					EmitUnpopulated: true,
+				},
+				UnmarshalOptions: protojson.UnmarshalOptions{
+					DiscardUnknown: true,
+				},
+			},
+		}),
+		runtime.WithMetadata(gatewayMetadata),
+		// the response headers are set by the middlewares of gin.
+		runtime.WithOutgoingHeaderMatcher(func(string) (string, bool) {
+			return "", false
+		}),
+		runtime.WithForwardResponseOption(gatewayForwardResponse),
+		runtime.WithErrorHandler(gatewayErrorHandler),
+		runtime.WithRoutingErrorHandler(gatewayRoutingErrorHandler),
+	)
+
+	if err := pb.RegisterBackendHandlerClient(context.Background(), mux, pb.NewBackendClient(conn)); err != nil {
+		return nil, fmt.Errorf("failed to register gateway: %w", err)
+	}
+
+	return mux, nil
+}
+
+// gateway serves the route with the gateway once the middlewares of the
+// route let the request through.
+func (h *Handler) gateway(c *gin.Context) {
+	body := &gatewayBody{ReadCloser: c.Request.Body}
+	c.Set(gatewayBodyCtx, body)
+
+	req := c.Request.Clone(context.WithValue(c.Request.Context(), ginContextKey{}, c))
+	req.Body = body
+	// the routes of the groups end with a slash, the http rules do not.
+	if path := strings.TrimSuffix(req.URL.Path, "/"); path != "" {
+		req.URL.Path = path
+	}
+
+	h.gatewayMux.ServeHTTP(c.Writer, req)
+}
+
+// gatewayBody keeps the error the body was read with, the gateway only passes
+// its message on in the status of the call.
+type gatewayBody struct {
+	io.ReadCloser
+	err error
+}
+
+func (b *gatewayBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		b.err = err
+	}
+
+	return n, err
+}
+
+// gatewayMetadata passes the client of the request as resolved by the
+// middlewares to the call, the client IP takes the trusted proxies into
+// account. The gRPC api knows no cookies, the access token of the session
+// cookie is passed as the Authorization header would be.
+func gatewayMetadata(ctx context.Context, _ *http.Request) metadata.MD {
+	c, ok := ctx.Value(ginContextKey{}).(*gin.Context)
+	if !ok {
+		return nil
+	}
+
+	client := clientinfo.FromContext(c)
+
+	md := metadata.Pairs(
+		requestid.Header, client.RequestID,
+		grpchandler.MetadataClientIP, client.IP,
+		grpchandler.MetadataUserAgent, client.UserAgent,
+		clientinfo.DeviceIDHeader, client.DeviceID,
+		"accept-language", i18n.LocaleFromContext(c),
+	)
+
+	if token := c.GetString(cookieTokenCtx); token != "" {
+		md.Set("authorization", authorizationTypeBearer+" "+token)
+	}
+
+	return md
+}
+
+// gatewayForwardResponse runs the hook the middlewares of the route left in
+// the gin context.
+func gatewayForwardResponse(ctx context.Context, _ http.ResponseWriter, msg proto.Message) error {
+	c, ok := ctx.Value(ginContextKey{}).(*gin.Context)
+	if !ok {
+		return nil
+	}
+
+	hook, ok := c.Value(gatewayResponseHookCtx).(gatewayResponseHook)
+	if !ok {
+		return nil
+	}
+
+	return hook(c, msg)
+}
+
+// gatewayErrorHandler hands the error of the call back to gin, errorMiddleware
+// answers it as the errors of the other routes.
+func gatewayErrorHandler(
+	ctx context.Context,
+	mux *runtime.ServeMux,
+	marshaler runtime.Marshaler,
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+) {
+	c, ok := ctx.Value(ginContextKey{}).(*gin.Context)
+	if !ok {
+		runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, err)
+
+		return
+	}
+
+	newErrorResponse(c, gatewayError(c, err))
+}
+
+func gatewayRoutingErrorHandler(
+	ctx context.Context,
+	mux *runtime.ServeMux,
+	marshaler runtime.Marshaler,
+	w http.ResponseWriter,
+	r *http.Request,
+	httpStatus int,
+) {
+	c, ok := ctx.Value(ginContextKey{}).(*gin.Context)
+	if !ok {
+		runtime.DefaultRoutingErrorHandler(ctx, mux, marshaler, w, r, httpStatus)
+
+		return
+	}
+
+	switch httpStatus {
+	case http.StatusMethodNotAllowed:
+		newErrorResponse(c, domain.ErrMethodNotAllowed)
+	case http.StatusBadRequest:
+		newErrorResponse(c, domain.ErrInvalidInput)
+	default:
+		newErrorResponse(c, domain.ErrRouteNotFound)
+	}
+}
+
+// callError is the error of a call answered with the reason of a domain
+// error, it keeps the message of the status as the detail.
+type callError struct {
+	err     error
+	message string
+}
+
+func (e *callError) Error() string {
+	return e.message
+}
+
+func (e *callError) Unwrap() error {
+	return e.err
+}
+
+// gatewayError turns the status of the call back into the domain error of
+// its reason. The statuses without a reason are made by the gateway itself,
+// it only fails to decode the request.
+func gatewayError(c *gin.Context, err error) error {
+	st := status.Convert(err)
+
+	var (
+		reason     string
+		retryDelay *errdetails.RetryInfo
+	)
+
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			reason = detail.GetReason()
+		case *errdetails.RetryInfo:
+			retryDelay = detail
+		}
+	}
+
+	if reason == "" {
+		if st.Code() == codes.InvalidArgument {
+			logger.FromContext(c).Debug("failed to decode request", logger.Err(err))
+
+			// the body is over the limit of bodyLimitMiddleware, its size
+			// was not known up front.
+			var maxBytesErr *http.MaxBytesError
+			if body, ok := c.Value(gatewayBodyCtx).(*gatewayBody); ok && errors.As(body.err, &maxBytesErr) {
+				return domain.ErrRequestTooLarge
+			}
+
+			return domain.ErrInvalidInput
+		}
+
+		return err
+	}
+
+	for _, mapping := range problemMappings {
+		if mapping.code != reason {
+			continue
+		}
+
+		if retryDelay != nil {
+			return &callError{
+				err:     domain.NewThrottledError(mapping.err, retryDelay.GetRetryDelay().AsDuration()),
+				message: st.Message(),
+			}
+		}
+
+		return &callError{err: mapping.err, message: st.Message()}
+	}
+
+	return errors.New(st.Message())
+}
+
+// gatewayMarshaler answers the calls without a result with an empty body,
+// as the REST api always did.
+type gatewayMarshaler struct {
+	*runtime.JSONPb
+}
+
+func (m *gatewayMarshaler) Marshal(v any) ([]byte, error) {
+	if msg, ok := v.(proto.Message); ok && msg.ProtoReflect().Descriptor().Fields().Len() == 0 {
+		return nil, nil
+	}
+
+	return m.JSONPb.Marshal(v)
+}
@@ -0,0 +1,242 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/b0shka/backend/internal/config"
+	"github.com/b0shka/backend/internal/domain"
+	domain_auth "github.com/b0shka/backend/internal/domain/auth"
+	grpchandler "github.com/b0shka/backend/internal/handler/grpc"
+	"github.com/b0shka/backend/internal/service"
+	mock_service "github.com/b0shka/backend/internal/service/mocks"
+	"github.com/b0shka/backend/pkg/auth"
+	"github.com/b0shka/backend/pkg/i18n"
+	"github.com/b0shka/backend/pkg/requestid"
+	"github.com/b0shka/backend/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestGatewayHandler serves the gateway routes with the gRPC api of the
+// services, as the app does.
+func newTestGatewayHandler(t *testing.T, services *service.Services, tokenManager auth.Manager) *Handler {
+	t.Helper()
+
+	grpcHandler := grpchandler.NewHandler(services, tokenManager, nil, config.RateLimitConfig{}, nil)
+	server := grpcHandler.NewServer(&config.Config{})
+	t.Cleanup(server.Stop)
+
+	conn, err := grpchandler.DialGateway(server)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	gateway, err := NewGateway(conn)
+	require.NoError(t, err)
+
+	return &Handler{
+		services:     services,
+		tokenManager: tokenManager,
+		gatewayMux:   gateway,
+	}
+}
+
+func TestHandler_updateUserLocale(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name         string
+		body         gin.H
+		mockBehavior func(s *mock_service.MockUsers)
+		statusCode   int
+		responseBody string
+	}{
+		{
+			name: "ok",
+			body: gin.H{"locale": "ru"},
+			mockBehavior: func(s *mock_service.MockUsers) {
+				s.EXPECT().UpdateLocale(gomock.Any(), userID, "ru").Return(nil)
+			},
+			statusCode:   http.StatusOK,
+			responseBody: "",
+		},
+		{
+			name: "unsupported locale",
+			body: gin.H{"locale": "de"},
+			mockBehavior: func(s *mock_service.MockUsers) {
+				s.EXPECT().UpdateLocale(gomock.Any(), userID, "de").Return(domain.ErrUnsupportedLocale)
+			},
+			statusCode:   http.StatusBadRequest,
+			responseBody: problemBody(t, domain.ErrUnsupportedLocale),
+		},
+		{
+			name: "user not found",
+			body: gin.H{"locale": "ru"},
+			mockBehavior: func(s *mock_service.MockUsers) {
+				s.EXPECT().UpdateLocale(gomock.Any(), userID, "ru").Return(domain.ErrUserNotFound)
+			},
+			statusCode:   http.StatusNotFound,
+			responseBody: problemBody(t, domain.ErrUserNotFound),
+		},
+		{
+			name: "empty locale",
+			body: gin.H{"locale": ""},
+			mockBehavior: func(s *mock_service.MockUsers) {
+				s.EXPECT().UpdateLocale(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			statusCode:   http.StatusBadRequest,
+			responseBody: problemBody(t, domain.ErrInvalidInput),
+		},
+		{
+			name: "malformed body",
+			body: gin.H{"locale": 42},
+			mockBehavior: func(s *mock_service.MockUsers) {
+				s.EXPECT().UpdateLocale(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			statusCode:   http.StatusBadRequest,
+			responseBody: problemBody(t, domain.ErrInvalidInput),
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			defer mockCtl.Finish()
+
+			secretKey, err := utils.RandomString(32)
+			require.NoError(t, err)
+
+			tokenManager, err := auth.NewJWTManager(secretKey)
+			require.NoError(t, err)
+
+			usersService := mock_service.NewMockUsers(mockCtl)
+			testCase.mockBehavior(usersService)
+
+			handler := newTestGatewayHandler(t, &service.Services{Users: usersService}, tokenManager)
+
+			router := newTestRouter()
+			handler.initUsersRoutes(router.Group("/api/v1"), config.RateLimitPolicy{}, nil)
+
+			data, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/users/locale", bytes.NewReader(data))
+			addAuthorizationHeader(t, req, tokenManager, authorizationTypeBearer, userID, time.Minute)
+
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, testCase.statusCode, recorder.Code)
+			require.Equal(t, testCase.responseBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHandler_gatewayMetadata(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	var (
+		gotRequestID, gotLocale string
+		gotInput                domain_auth.SendCodeEmailInput
+	)
+
+	authService := mock_service.NewMockAuth(mockCtl)
+	authService.EXPECT().
+		SendCodeEmail(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, inp domain_auth.SendCodeEmailInput) error {
+			gotRequestID = requestid.FromContext(ctx)
+			gotLocale = i18n.LocaleFromContext(ctx)
+			gotInput = inp
+
+			return nil
+		})
+
+	bundle, err := i18n.NewBundle("en", map[string]i18n.Catalog{"en": {}, "ru": {}})
+	require.NoError(t, err)
+
+	services := &service.Services{Auth: authService}
+	server := grpchandler.NewHandler(services, nil, nil, config.RateLimitConfig{}, bundle).NewServer(&config.Config{})
+	t.Cleanup(server.Stop)
+
+	conn, err := grpchandler.DialGateway(server)
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	gateway, err := NewGateway(conn)
+	require.NoError(t, err)
+
+	handler := &Handler{gatewayMux: gateway}
+
+	router := newTestRouter()
+	router.Use(requestIDMiddleware, localeMiddleware(bundle))
+	handler.initAuthRoutes(router.Group("/api/v1"), config.RateLimitConfig{}, nil)
+
+	requestID := requestid.New()
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/send-code", bytes.NewBufferString(`{"email":"email@ya.ru"}`))
+	req.Header.Set(requestid.Header, requestID)
+	req.Header.Set("Accept-Language", "ru-RU")
+
+	router.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, requestID, recorder.Header().Get(requestid.Header))
+	require.Equal(t, requestID, gotRequestID)
+	require.Equal(t, "ru", gotLocale)
+	require.Equal(t, domain_auth.NewSendCodeEmailInput("email@ya.ru", "192.0.2.1"), gotInput)
+}
+
+func TestHandler_gatewayRouteNotFound(t *testing.T) {
+	handler := newTestGatewayHandler(t, &service.Services{}, nil)
+
+	router := newTestRouter()
+	router.POST("/api/v1/unknown", handler.gateway)
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/unknown", nil)
+
+	router.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+	require.Equal(t, problemBody(t, domain.ErrRouteNotFound), recorder.Body.String())
+}
+
+func TestHandler_gatewayBodyLimit(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	authService := mock_service.NewMockAuth(mockCtl)
+	authService.EXPECT().SendCodeEmail(gomock.Any(), gomock.Any()).Times(0)
+
+	handler := newTestGatewayHandler(t, &service.Services{Auth: authService}, nil)
+
+	router := newTestRouter()
+	router.Use(bodyLimitMiddleware(1))
+	handler.initAuthRoutes(router.Group("/api/v1"), config.RateLimitConfig{}, nil)
+
+	body := `{"email":"` + strings.Repeat("a", 1<<20) + `@ya.ru"}`
+
+	recorder := httptest.NewRecorder()
+	// the body is chunked, its size is only known once it is read.
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/send-code", io.MultiReader(strings.NewReader(body)))
+	require.Equal(t, int64(-1), req.ContentLength)
+
+	router.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+	require.Equal(t, problemBody(t, domain.ErrRequestTooLarge), recorder.Body.String())
+}
@@ -2,64 +2,128 @@ package http
 
 import (
 	"fmt"
+	"log/slog"
 	"net/http"
 
 	"github.com/b0shka/backend/docs"
 	"github.com/b0shka/backend/internal/config"
+	"github.com/b0shka/backend/internal/domain"
+	grpchandler "github.com/b0shka/backend/internal/handler/grpc"
 	"github.com/b0shka/backend/internal/service"
 	"github.com/b0shka/backend/pkg/auth"
+	"github.com/b0shka/backend/pkg/clientinfo"
+	"github.com/b0shka/backend/pkg/health"
+	"github.com/b0shka/backend/pkg/i18n"
+	"github.com/b0shka/backend/pkg/limiter"
+	"github.com/b0shka/backend/pkg/logger"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 type Handler struct {
 	services     *service.Services
 	tokenManager auth.Manager
+	limiter      limiter.Limiter
+	i18n         *i18n.Bundle
+	probe        *health.Probe
+	gatewayMux   http.Handler
+	ipResolver   *clientinfo.IPResolver
 }
 
 func NewHandler(
 	services *service.Services,
 	tokenManager auth.Manager,
+	limiter limiter.Limiter,
+	bundle *i18n.Bundle,
+	probe *health.Probe,
+	gateway http.Handler,
+	ipResolver *clientinfo.IPResolver,
 ) *Handler {
 	return &Handler{
 		services:     services,
 		tokenManager: tokenManager,
+		limiter:      limiter,
+		i18n:         bundle,
+		probe:        probe,
+		gatewayMux:   gateway,
+		ipResolver:   ipResolver,
 	}
 }
 
-func (h *Handler) InitRoutes(cfg *config.Config) *gin.Engine {
-	router := gin.Default()
+// InitRoutes fails on the CORS policy the browsers would refuse.
+func (h *Handler) InitRoutes(cfg *config.Config) (*gin.Engine, error) {
+	cors, err := newCORSPolicy(cfg.HTTP.CORS)
+	if err != nil {
+		return nil, err
+	}
+
+	router := gin.New()
+	// lets the services that get the gin context find the request span and
+	// the request logger.
+	router.ContextWithFallback = true
 
 	router.Use(
 		gin.Recovery(),
-		gin.Logger(),
-		corsMiddleware,
+		otelgin.Middleware(cfg.Tracing.ServiceName),
+		securityHeadersMiddleware(cfg.HTTP.SecurityHeaders),
+		requestIDMiddleware,
+		clientInfoMiddleware(h.ipResolver),
+		localeMiddleware(h.i18n),
+		loggerMiddleware,
+		metricsMiddleware,
+		errorMiddleware(h.i18n),
+		cors.middleware,
+		bodyLimitMiddleware(cfg.HTTP.MaxBodyMegabytes),
 	)
 
+	// the client IP is resolved by clientInfoMiddleware, gin must not trust
+	// the headers of any peer.
+	if err := router.SetTrustedProxies(nil); err != nil {
+		slog.Error("failed to set trusted proxies", logger.Err(err))
+	}
+
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(func(c *gin.Context) {
+		newErrorResponse(c, domain.ErrRouteNotFound)
+	})
+	router.NoMethod(func(c *gin.Context) {
+		newErrorResponse(c, domain.ErrMethodNotAllowed)
+	})
+
 	docs.SwaggerInfo.Host = fmt.Sprintf("%s:%s", cfg.HTTP.Host, cfg.HTTP.Port)
 
 	if cfg.Environment != config.EnvLocal {
 		docs.SwaggerInfo.Host = cfg.HTTP.Host
 	}
 
-	// if cfg.Environment != config.EnvProd {
-	// 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	// }
-
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	if cfg.Environment != config.EnvProd {
+		router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+		// the auth and the users routes are documented by the spec generated
+		// from the protos.
+		router.GET("/openapi.json", func(c *gin.Context) {
+			c.Data(http.StatusOK, "application/json", grpchandler.OpenAPISpec())
+		})
+		router.GET("/openapi/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("/openapi.json")))
+	}
 
 	router.GET("/ping", func(c *gin.Context) {
 		c.String(http.StatusOK, "pong")
 	})
 
+	h.initHealthRoutes(router)
+
+	cookies := newSessionCookies(cfg.Auth)
+
 	api := router.Group("/api/v1")
 	{
-		h.initAuthRoutes(api)
-		h.initUsersRoutes(api)
+		h.initAuthRoutes(api, cfg.RateLimit, cookies)
+		h.initUsersRoutes(api, cfg.RateLimit.Users, cookies)
+		h.initAdminRoutes(api, cfg.Admin.APIKey, cfg.RateLimit.Admin)
 	}
 
-	return router
+	return router, nil
 }
 
 // func parseIdFromPath(c *gin.Context, param string) (primitive.ObjectID, error) {
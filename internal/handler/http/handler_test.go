@@ -1,26 +1,46 @@
 package http_test
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/b0shka/backend/internal/config"
 	handler "github.com/b0shka/backend/internal/handler/http"
 	"github.com/b0shka/backend/internal/service"
 	"github.com/b0shka/backend/pkg/auth"
+	"github.com/b0shka/backend/pkg/clientinfo"
+	"github.com/b0shka/backend/pkg/health"
+	"github.com/b0shka/backend/pkg/i18n"
+	"github.com/b0shka/backend/pkg/limiter"
 	"github.com/stretchr/testify/require"
 )
 
+func newTestBundle(t *testing.T) *i18n.Bundle {
+	t.Helper()
+
+	bundle, err := i18n.NewBundle("en", map[string]i18n.Catalog{
+		"en": {"errors.route_not_found": "The route does not exist."},
+		"ru": {"errors.route_not_found": "Маршрут не существует."},
+	})
+	require.NoError(t, err)
+
+	return bundle
+}
+
 func TestNewHandler(t *testing.T) {
-	h := handler.NewHandler(&service.Services{}, &auth.PasetoManager{})
+	h := handler.NewHandler(&service.Services{}, &auth.PasetoManager{}, limiter.NewMemoryLimiter(), newTestBundle(t), health.NewProbe(time.Second), nil, &clientinfo.IPResolver{})
 
 	require.IsType(t, &handler.Handler{}, h)
 }
 
 func TestNewHandler_InitRoutes(t *testing.T) {
-	h := handler.NewHandler(&service.Services{}, &auth.PasetoManager{})
-	router := h.InitRoutes(&config.Config{})
+	h := handler.NewHandler(&service.Services{}, &auth.PasetoManager{}, limiter.NewMemoryLimiter(), newTestBundle(t), health.NewProbe(time.Second), nil, &clientinfo.IPResolver{})
+	router, err := h.InitRoutes(&config.Config{})
+	require.NoError(t, err)
 
 	ts := httptest.NewServer(router)
 	defer ts.Close()
@@ -33,3 +53,125 @@ func TestNewHandler_InitRoutes(t *testing.T) {
 
 	require.Equal(t, http.StatusOK, res.StatusCode)
 }
+
+func TestNewHandler_InitRoutesOpenAPI(t *testing.T) {
+	h := handler.NewHandler(&service.Services{}, &auth.PasetoManager{}, limiter.NewMemoryLimiter(), newTestBundle(t), health.NewProbe(time.Second), nil, &clientinfo.IPResolver{})
+	router, err := h.InitRoutes(&config.Config{})
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var spec struct {
+		Paths map[string]any `json:"paths"`
+	}
+
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &spec))
+	require.Contains(t, spec.Paths, "/api/v1/auth/send-code")
+	require.Contains(t, spec.Paths, "/api/v1/users")
+}
+
+func TestNewHandler_InitRoutesDocsProd(t *testing.T) {
+	h := handler.NewHandler(&service.Services{}, &auth.PasetoManager{}, limiter.NewMemoryLimiter(), newTestBundle(t), health.NewProbe(time.Second), nil, &clientinfo.IPResolver{})
+	router, err := h.InitRoutes(&config.Config{Environment: config.EnvProd})
+	require.NoError(t, err)
+
+	for _, path := range []string{"/openapi.json", "/openapi/index.html", "/swagger/index.html"} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+		require.Equal(t, http.StatusNotFound, recorder.Code, path)
+	}
+}
+
+func TestNewHandler_InitRoutesNotFound(t *testing.T) {
+	h := handler.NewHandler(&service.Services{}, &auth.PasetoManager{}, limiter.NewMemoryLimiter(), newTestBundle(t), health.NewProbe(time.Second), nil, &clientinfo.IPResolver{})
+	router, err := h.InitRoutes(&config.Config{})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		method     string
+		statusCode int
+		code       string
+	}{
+		"/unknown": {http.MethodGet, http.StatusNotFound, "route_not_found"},
+		"/ping":    {http.MethodPost, http.StatusMethodNotAllowed, "method_not_allowed"},
+	}
+
+	for path, testCase := range tests {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(testCase.method, path, nil))
+
+		require.Equal(t, testCase.statusCode, recorder.Code)
+		require.Equal(t, "application/problem+json", recorder.Header().Get("Content-Type"))
+		require.Contains(t, recorder.Body.String(), `"code":"`+testCase.code+`"`)
+		require.NotEmpty(t, recorder.Header().Get("X-Request-ID"))
+	}
+}
+
+func TestNewHandler_InitRoutesLocalized(t *testing.T) {
+	h := handler.NewHandler(&service.Services{}, &auth.PasetoManager{}, limiter.NewMemoryLimiter(), newTestBundle(t), health.NewProbe(time.Second), nil, &clientinfo.IPResolver{})
+	router, err := h.InitRoutes(&config.Config{})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		acceptLanguage string
+		locale         string
+		detail         string
+	}{
+		"Default":     {"", "en", "The route does not exist."},
+		"Russian":     {"ru-RU,ru;q=0.9,en;q=0.8", "ru", "Маршрут не существует."},
+		"Unsupported": {"de-DE", "en", "The route does not exist."},
+	}
+
+	for name, testCase := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/unknown", nil)
+			req.Header.Set("Accept-Language", testCase.acceptLanguage)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, http.StatusNotFound, recorder.Code)
+			require.Equal(t, testCase.locale, recorder.Header().Get("Content-Language"))
+			require.Contains(t, recorder.Body.String(), `"detail":"`+testCase.detail+`"`)
+		})
+	}
+}
+
+func TestNewHandler_InitRoutesHealth(t *testing.T) {
+	probe := health.NewProbe(time.Second)
+	probe.Register("postgresql", func(context.Context) error {
+		return nil
+	})
+
+	h := handler.NewHandler(&service.Services{}, &auth.PasetoManager{}, limiter.NewMemoryLimiter(), newTestBundle(t), probe, nil, &clientinfo.IPResolver{})
+	router, err := h.InitRoutes(&config.Config{})
+	require.NoError(t, err)
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+		return recorder
+	}
+
+	recorder := serve("/healthz")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `{"status":"up"}`, recorder.Body.String())
+
+	recorder = serve("/readyz")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `"postgresql":{"status":"up"`)
+
+	probe.Drain()
+
+	recorder = serve("/readyz")
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	require.JSONEq(t, `{"status":"draining"}`, recorder.Body.String())
+
+	recorder = serve("/healthz")
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/b0shka/backend/pkg/health"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) initHealthRoutes(router *gin.Engine) {
+	router.GET("/healthz", h.healthz)
+	router.GET("/readyz", h.readyz)
+}
+
+// @Summary		Liveness
+// @Tags			health
+// @Description	the process is up, the dependencies are not checked
+// @ModuleID		healthz
+// @Produce		json
+// @Success		200	{object}	health.Report
+// @Router			/healthz [get]
+func (h *Handler) healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, health.Report{Status: health.StatusUp})
+}
+
+// @Summary		Readiness
+// @Tags			health
+// @Description	the dependencies are up and the service is not shutting down
+// @ModuleID		readyz
+// @Produce		json
+// @Success		200	{object}	health.Report
+// @Failure		503	{object}	health.Report
+// @Router			/readyz [get]
+func (h *Handler) readyz(c *gin.Context) {
+	report, ready := h.probe.Check(c)
+	if !ready {
+		c.JSON(http.StatusServiceUnavailable, report)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -1,40 +1,93 @@
 package http
 
 import (
-	"github.com/b0shka/backend/internal/domain/auth"
-	"github.com/b0shka/backend/internal/domain/user"
+	"github.com/b0shka/backend/internal/domain/task"
+	"github.com/b0shka/backend/internal/domain/webhook"
 )
 
-func NewSendCodeEmailInput(req SendCodeRequest) auth.SendCodeEmailInput {
-	return auth.NewSendCodeEmailInput(req.Email)
+func NewCreateSubscriptionInput(req CreateWebhookRequest) webhook.CreateSubscriptionInput {
+	return webhook.NewCreateSubscriptionInput(req.URL, req.Secret, req.EventTypes)
 }
 
-func NewSignInInput(req SignInRequest) auth.SignInInput {
-	return auth.NewSignInInput(req.Email, req.SecretCode)
+func NewWebhookResponse(out webhook.Subscription) WebhookResponse {
+	return WebhookResponse{
+		ID:           out.ID,
+		URL:          out.URL,
+		EventTypes:   out.EventTypes,
+		IsActive:     out.IsActive,
+		FailureCount: out.FailureCount,
+		CreatedAt:    out.CreatedAt,
+	}
 }
 
-func NewSignInResponse(out auth.SignInOutput) SignInResponse {
-	return SignInResponse{
-		SessionID:    out.SessionID,
-		RefreshToken: out.RefreshToken,
-		AccessToken:  out.AccessToken,
+func NewWebhookDeliveryResponse(out webhook.DeliveryWithAttempts) WebhookDeliveryResponse {
+	attempts := make([]WebhookAttemptResponse, 0, len(out.AttemptsLog))
+	for _, attempt := range out.AttemptsLog {
+		attempts = append(attempts, WebhookAttemptResponse{
+			ResponseCode: attempt.ResponseCode,
+			Error:        attempt.Error,
+			DurationMs:   attempt.Duration.Milliseconds(),
+			CreatedAt:    attempt.CreatedAt,
+		})
+	}
+
+	return WebhookDeliveryResponse{
+		ID:        out.ID,
+		EventID:   out.EventID,
+		EventType: out.EventType,
+		Status:    out.Status,
+		Attempts:  attempts,
+		CreatedAt: out.CreatedAt,
+		UpdatedAt: out.UpdatedAt,
+		RequestID: out.RequestID,
 	}
 }
 
-func NewRefreshTokenInput(req RefreshTokenRequest) auth.RefreshTokenInput {
-	return auth.NewRefreshTokenInput(req.RefreshToken)
+func NewQueueResponse(out task.Queue) QueueResponse {
+	return QueueResponse{
+		Name:      out.Name,
+		Size:      out.Size,
+		Pending:   out.Pending,
+		Active:    out.Active,
+		Scheduled: out.Scheduled,
+		Retry:     out.Retry,
+		Archived:  out.Archived,
+		Processed: out.Processed,
+		Failed:    out.Failed,
+		Paused:    out.Paused,
+	}
 }
 
-func NewRefreshTokenResponse(out auth.RefreshTokenOutput) RefreshTokenResponse {
-	return RefreshTokenResponse{
-		AccessToken: out.AccessToken,
+func NewListTasksInput(queue string, req ListTasksRequest) task.ListTasksInput {
+	state := req.State
+	if state == "" {
+		state = task.StateArchived
 	}
+
+	page := req.Page
+	if page == 0 {
+		page = 1
+	}
+
+	pageSize := req.PageSize
+	if pageSize == 0 {
+		pageSize = defaultTasksPageSize
+	}
+
+	return task.NewListTasksInput(queue, state, page, pageSize)
 }
 
-func NewGetUserResponse(out user.User) GetUserResponse {
-	return GetUserResponse{
-		ID:        out.ID,
-		Email:     out.Email,
-		CreatedAt: out.CreatedAt,
+func NewTaskResponse(out task.Task) TaskResponse {
+	return TaskResponse{
+		ID:            out.ID,
+		Queue:         out.Queue,
+		Type:          out.Type,
+		State:         out.State,
+		Payload:       out.Payload,
+		MaxRetry:      out.MaxRetry,
+		Retried:       out.Retried,
+		LastError:     out.LastError,
+		LastFailedAt:  out.LastFailedAt,
+		NextProcessAt: out.NextProcessAt,
 	}
 }
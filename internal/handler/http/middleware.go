@@ -1,45 +1,201 @@
 package http
 
 import (
+	"crypto/subtle"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/b0shka/backend/internal/config"
 	"github.com/b0shka/backend/internal/domain"
+	"github.com/b0shka/backend/internal/metrics"
 	"github.com/b0shka/backend/pkg/auth"
+	"github.com/b0shka/backend/pkg/clientinfo"
+	"github.com/b0shka/backend/pkg/i18n"
+	"github.com/b0shka/backend/pkg/logger"
+	"github.com/b0shka/backend/pkg/requestid"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
 	authorizationHeaderKey  = "Authorization"
 	authorizationTypeBearer = "Bearer"
+	adminKeyHeaderKey       = "X-Admin-Key"
 
 	userCtx = "userCtx"
+	// cookieTokenCtx holds the access token of the requests authenticated by
+	// the session cookie.
+	cookieTokenCtx = "cookieTokenCtx"
+
+	unmatchedRoute = "unmatched"
 )
 
-func corsMiddleware(c *gin.Context) {
-	c.Header("Access-Control-Allow-Origin", "*")
-	c.Header("Access-Control-Allow-Methods", "*")
-	c.Header("Access-Control-Allow-Headers", "*")
-	c.Header("Content-Type", "application/json")
+// securityHeadersMiddleware keeps the browsers from sniffing the content type
+// and framing the responses, HSTS makes them use https only.
+func securityHeadersMiddleware(cfg config.SecurityHeadersConfig) gin.HandlerFunc {
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = fmt.Sprintf("max-age=%d; includeSubDomains", int(cfg.HSTSMaxAge.Seconds()))
+	}
+
+	return func(c *gin.Context) {
+		c.Header("X-Content-Type-Options", "nosniff")
+		c.Header("X-Frame-Options", "DENY")
+
+		if hsts != "" {
+			c.Header("Strict-Transport-Security", hsts)
+		}
+
+		if cfg.ContentSecurityPolicy != "" {
+			c.Header("Content-Security-Policy", cfg.ContentSecurityPolicy)
+		}
+
+		if cfg.ReferrerPolicy != "" {
+			c.Header("Referrer-Policy", cfg.ReferrerPolicy)
+		}
+
+		c.Next()
+	}
+}
+
+// bodyLimitMiddleware rejects the bodies larger than the limit up front when
+// their length is known, the reads of the others fail past the limit. Zero
+// megabytes disable the limit.
+func bodyLimitMiddleware(megabytes int) gin.HandlerFunc {
+	limit := int64(megabytes) << 20
+
+	return func(c *gin.Context) {
+		if limit <= 0 {
+			return
+		}
+
+		if c.Request.ContentLength > limit {
+			newErrorResponse(c, domain.ErrRequestTooLarge)
+
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+	}
+}
+
+// requestIDMiddleware keeps the request ID sent by the client or by the proxy
+// in front of the service, or generates one when it is missing or malformed.
+// The ID is returned in the response header and carried by the request
+// context, so that the logs, the error bodies, the sessions and the tasks of
+// the request can be tied back to it.
+func requestIDMiddleware(c *gin.Context) {
+	id := c.GetHeader(requestid.Header)
+	if !requestid.Valid(id) {
+		id = requestid.New()
+	}
+
+	ctx := requestid.WithContext(c.Request.Context(), id)
+	ctx = logger.With(ctx, logger.KeyRequestID, id)
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("http.request_id", id))
+
+	c.Request = c.Request.WithContext(ctx)
+	c.Header(requestid.Header, id)
+
+	c.Next()
+}
+
+// clientInfoMiddleware puts the client of the request in the request
+// context for the services, the logs and the rate limits. The forwarding
+// headers are only read from the trusted proxies.
+func clientInfoMiddleware(resolver *clientinfo.IPResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		deviceID := c.GetHeader(clientinfo.DeviceIDHeader)
+		if !clientinfo.ValidDeviceID(deviceID) {
+			deviceID = ""
+		}
+
+		c.Request = c.Request.WithContext(clientinfo.WithContext(c.Request.Context(), clientinfo.Info{
+			IP:        resolver.Resolve(c.Request.RemoteAddr, c.Request.Header),
+			UserAgent: c.Request.UserAgent(),
+			DeviceID:  deviceID,
+			RequestID: requestid.FromContext(c.Request.Context()),
+		}))
+
+		c.Next()
+	}
+}
+
+// localeMiddleware negotiates the locale of the request from the
+// Accept-Language header. The locale translates the error details and is
+// carried by the tasks, so that the emails of the users without a saved
+// preference are rendered in it.
+func localeMiddleware(bundle *i18n.Bundle) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := bundle.Match(c.GetHeader("Accept-Language"))
+		c.Request = c.Request.WithContext(i18n.WithLocale(c.Request.Context(), locale))
 
-	if c.Request.Method != http.MethodOptions {
 		c.Next()
-	} else {
-		c.AbortWithStatus(http.StatusOK)
 	}
 }
 
-func userIdentity(tokenManager auth.Manager) gin.HandlerFunc {
+// loggerMiddleware puts the request logger in the request context, so that
+// the handlers and the services log with the request attributes, and logs
+// every handled request.
+func loggerMiddleware(c *gin.Context) {
+	start := time.Now()
+	log := logger.FromContext(c.Request.Context()).With(
+		"method", c.Request.Method,
+		"path", c.Request.URL.Path,
+		"client_ip", clientinfo.FromContext(c.Request.Context()).IP,
+	)
+
+	if spanContext := trace.SpanContextFromContext(c.Request.Context()); spanContext.IsValid() {
+		log = log.With("trace_id", spanContext.TraceID().String())
+	}
+
+	c.Request = c.Request.WithContext(logger.WithContext(c.Request.Context(), log))
+
+	c.Next()
+
+	logger.FromContext(c.Request.Context()).Info("handled request",
+		"status", c.Writer.Status(),
+		"latency", time.Since(start),
+	)
+}
+
+// metricsMiddleware records every request by its route pattern, requests
+// that match no route share a single label.
+func metricsMiddleware(c *gin.Context) {
+	start := time.Now()
+
+	c.Next()
+
+	route := c.FullPath()
+	if route == "" {
+		route = unmatchedRoute
+	}
+
+	metrics.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+}
+
+// userIdentity takes the access token from the Authorization header, or from
+// the session cookie when the cookie mode is on. The requests authenticated
+// by the cookie must pass the CSRF check.
+func userIdentity(tokenManager auth.Manager, cookies *sessionCookies) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		payload, err := parseAuthHeader(c, tokenManager)
+		if errors.Is(err, domain.ErrEmptyAuthHeader) && cookies != nil {
+			payload, err = parseAuthCookie(c, tokenManager, err)
+		}
+
 		if err != nil {
-			newResponse(c, http.StatusUnauthorized, err.Error())
+			newErrorResponse(c, err)
 
 			return
 		}
 
 		c.Set(userCtx, payload)
+		c.Request = c.Request.WithContext(logger.With(c.Request.Context(), logger.KeyUserID, payload.UserID))
 	}
 }
 
@@ -56,12 +212,33 @@ func parseAuthHeader(c *gin.Context, tokenManager auth.Manager) (*auth.Payload,
 
 	authorizationType := headerParts[0]
 	if authorizationType != authorizationTypeBearer {
-		return nil, fmt.Errorf("unsupported authorization type: %s", authorizationType)
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnsupportedAuthType, authorizationType)
 	}
 
 	return tokenManager.VerifyToken(headerParts[1])
 }
 
+// parseAuthCookie returns the error of the header when there is no cookie.
+func parseAuthCookie(c *gin.Context, tokenManager auth.Manager, headerErr error) (*auth.Payload, error) {
+	token, err := c.Cookie(accessTokenCookie)
+	if err != nil || token == "" {
+		return nil, headerErr
+	}
+
+	if err := verifyCSRFToken(c); err != nil {
+		return nil, err
+	}
+
+	payload, err := tokenManager.VerifyToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Set(cookieTokenCtx, token)
+
+	return payload, nil
+}
+
 func getUserPayload(c *gin.Context) (*auth.Payload, error) {
 	return getPayloadByContext(c, userCtx)
 }
@@ -79,3 +256,22 @@ func getPayloadByContext(c *gin.Context, context string) (*auth.Payload, error)
 
 	return payload, nil
 }
+
+// adminIdentity guards the admin api with a static key. An empty configured
+// key denies every request so the api is closed unless explicitly enabled.
+func adminIdentity(apiKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(adminKeyHeaderKey)
+		if len(key) == 0 {
+			newErrorResponse(c, domain.ErrEmptyAdminKey)
+
+			return
+		}
+
+		if apiKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) != 1 {
+			newErrorResponse(c, domain.ErrInvalidAdminKey)
+
+			return
+		}
+	}
+}
@@ -1,13 +1,20 @@
 package http
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/b0shka/backend/internal/config"
+	"github.com/b0shka/backend/internal/domain"
 	"github.com/b0shka/backend/pkg/auth"
+	"github.com/b0shka/backend/pkg/clientinfo"
+	"github.com/b0shka/backend/pkg/requestid"
 	"github.com/b0shka/backend/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -15,6 +22,25 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// newTestRouter answers the errors of the handlers as InitRoutes does.
+func newTestRouter() *gin.Engine {
+	router := gin.New()
+	router.ContextWithFallback = true
+	router.Use(clientInfoMiddleware(&clientinfo.IPResolver{}), errorMiddleware(nil))
+
+	return router
+}
+
+// problemBody is the body of the response to a domain error.
+func problemBody(t *testing.T, err error) string {
+	t.Helper()
+
+	body, merr := json.Marshal(newProblem(err))
+	require.NoError(t, merr)
+
+	return string(body)
+}
+
 func addAuthorizationHeader(
 	t *testing.T,
 	request *http.Request,
@@ -54,7 +80,7 @@ func TestHandler_userIDentity(t *testing.T) {
 			setupAuth: func(t *testing.T, request *http.Request, tokenManager auth.Manager) {
 			},
 			statusCode:   401,
-			responseBody: `{"message":"empty authorization header"}`,
+			responseBody: problemBody(t, domain.ErrEmptyAuthHeader),
 		},
 		{
 			name: "unsupported authorization",
@@ -62,7 +88,7 @@ func TestHandler_userIDentity(t *testing.T) {
 				addAuthorizationHeader(t, request, tokenManager, "unsupported", userID, time.Minute)
 			},
 			statusCode:   401,
-			responseBody: fmt.Sprintf(`{"message":"unsupported authorization type: %s"}`, "unsupported"),
+			responseBody: problemBody(t, fmt.Errorf("%w: %s", domain.ErrUnsupportedAuthType, "unsupported")),
 		},
 		{
 			name: "invalid authorization format",
@@ -70,7 +96,7 @@ func TestHandler_userIDentity(t *testing.T) {
 				addAuthorizationHeader(t, request, tokenManager, "", userID, time.Minute)
 			},
 			statusCode:   401,
-			responseBody: `{"message":"invalid authorization header format"}`,
+			responseBody: problemBody(t, domain.ErrInvalidAuthHeaderFormat),
 		},
 		{
 			name: "expired token",
@@ -78,7 +104,7 @@ func TestHandler_userIDentity(t *testing.T) {
 				addAuthorizationHeader(t, request, tokenManager, authorizationTypeBearer, userID, -time.Minute)
 			},
 			statusCode:   401,
-			responseBody: `{"message":"token has expired"}`,
+			responseBody: problemBody(t, domain.ErrExpiredToken),
 		},
 	}
 
@@ -90,11 +116,11 @@ func TestHandler_userIDentity(t *testing.T) {
 			tokenManager, err := auth.NewPasetoManager(symmetricKey)
 			require.NoError(t, err)
 
-			router := gin.Default()
+			router := newTestRouter()
 
 			router.GET(
 				"/identity",
-				userIdentity(tokenManager),
+				userIdentity(tokenManager, nil),
 				func(c *gin.Context) {
 					c.Status(http.StatusOK)
 				},
@@ -166,3 +192,231 @@ func TestGetUserPayload(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		keepID bool
+	}{
+		{
+			name:   "client id",
+			header: "01HGW2N7EHJ0MZ0T6Y1KXQ3F4V",
+			keepID: true,
+		},
+		{
+			name:   "no id",
+			header: "",
+		},
+		{
+			name:   "malformed id",
+			header: "forged\nrequest_id=admin",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			router := newTestRouter()
+
+			var handlerID string
+
+			router.GET(
+				"/request",
+				requestIDMiddleware,
+				func(c *gin.Context) {
+					handlerID = requestid.FromContext(c)
+					newErrorResponse(c, domain.ErrInvalidInput)
+				},
+			)
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/request", nil)
+			req.Header.Set(requestid.Header, testCase.header)
+
+			router.ServeHTTP(recorder, req)
+
+			id := recorder.Header().Get(requestid.Header)
+			require.True(t, requestid.Valid(id))
+			require.Equal(t, id, handlerID)
+			require.JSONEq(t, fmt.Sprintf(
+				`{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid input body",`+
+					`"code":"invalid_input","request_id":%q}`, id,
+			), recorder.Body.String())
+
+			if testCase.keepID {
+				require.Equal(t, testCase.header, id)
+			} else {
+				require.NotEqual(t, testCase.header, id)
+			}
+		})
+	}
+}
+
+func TestClientInfoMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		info       clientinfo.Info
+	}{
+		{
+			name:       "direct",
+			remoteAddr: "203.0.113.7:4242",
+			headers: map[string]string{
+				"User-Agent":              "curl/8.0",
+				clientinfo.DeviceIDHeader: "device",
+			},
+			info: clientinfo.Info{IP: "203.0.113.7", UserAgent: "curl/8.0", DeviceID: "device"},
+		},
+		{
+			name:       "trusted proxy",
+			remoteAddr: "10.0.0.2:4242",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.7, 10.0.0.3"},
+			info:       clientinfo.Info{IP: "203.0.113.7"},
+		},
+		{
+			name:       "spoofed forwarded for",
+			remoteAddr: "198.51.100.1:4242",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			info:       clientinfo.Info{IP: "198.51.100.1"},
+		},
+		{
+			name:       "malformed device id",
+			remoteAddr: "203.0.113.7:4242",
+			headers:    map[string]string{clientinfo.DeviceIDHeader: "device\nforged=log"},
+			info:       clientinfo.Info{IP: "203.0.113.7"},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			resolver, err := clientinfo.NewIPResolver([]string{"10.0.0.0/8"}, []string{clientinfo.HeaderXForwardedFor})
+			require.NoError(t, err)
+
+			router := gin.New()
+			router.ContextWithFallback = true
+
+			var info clientinfo.Info
+
+			router.GET("/client", requestIDMiddleware, clientInfoMiddleware(resolver), func(c *gin.Context) {
+				info = clientinfo.FromContext(c)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/client", nil)
+			req.RemoteAddr = testCase.remoteAddr
+
+			for key, value := range testCase.headers {
+				req.Header.Set(key, value)
+			}
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, recorder.Header().Get(requestid.Header), info.RequestID)
+
+			info.RequestID = ""
+			require.Equal(t, testCase.info, info)
+		})
+	}
+}
+
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	router := newTestRouter()
+	router.Use(securityHeadersMiddleware(config.SecurityHeadersConfig{
+		HSTSMaxAge:            time.Hour,
+		ContentSecurityPolicy: "frame-ancestors 'none'",
+		ReferrerPolicy:        "no-referrer",
+	}))
+	router.GET("/headers", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/headers", nil))
+
+	require.Equal(t, "nosniff", recorder.Header().Get("X-Content-Type-Options"))
+	require.Equal(t, "DENY", recorder.Header().Get("X-Frame-Options"))
+	require.Equal(t, "max-age=3600; includeSubDomains", recorder.Header().Get("Strict-Transport-Security"))
+	require.Equal(t, "frame-ancestors 'none'", recorder.Header().Get("Content-Security-Policy"))
+	require.Equal(t, "no-referrer", recorder.Header().Get("Referrer-Policy"))
+
+	router = newTestRouter()
+	router.Use(securityHeadersMiddleware(config.SecurityHeadersConfig{}))
+	router.GET("/headers", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/headers", nil))
+
+	require.Equal(t, "nosniff", recorder.Header().Get("X-Content-Type-Options"))
+	require.Empty(t, recorder.Header().Get("Strict-Transport-Security"))
+	require.Empty(t, recorder.Header().Get("Content-Security-Policy"))
+}
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	type input struct {
+		Value string `json:"value"`
+	}
+
+	large := `{"value":"` + strings.Repeat("a", 1<<20) + `"}`
+
+	tests := []struct {
+		name         string
+		megabytes    int
+		body         io.Reader
+		statusCode   int
+		responseBody string
+	}{
+		{
+			name:       "ok",
+			megabytes:  1,
+			body:       strings.NewReader(`{"value":"a"}`),
+			statusCode: http.StatusOK,
+		},
+		{
+			name:         "content length",
+			megabytes:    1,
+			body:         strings.NewReader(large),
+			statusCode:   http.StatusRequestEntityTooLarge,
+			responseBody: problemBody(t, domain.ErrRequestTooLarge),
+		},
+		{
+			name:         "unknown length",
+			megabytes:    1,
+			body:         io.MultiReader(strings.NewReader(large)),
+			statusCode:   http.StatusRequestEntityTooLarge,
+			responseBody: problemBody(t, domain.ErrRequestTooLarge),
+		},
+		{
+			name:       "no limit",
+			body:       strings.NewReader(large),
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			router := newTestRouter()
+			router.POST("/body", bodyLimitMiddleware(testCase.megabytes), func(c *gin.Context) {
+				var inp input
+				if err := c.ShouldBindJSON(&inp); err != nil {
+					newInputErrorResponse(c, err)
+
+					return
+				}
+
+				c.Status(http.StatusOK)
+			})
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/body", testCase.body))
+
+			require.Equal(t, testCase.statusCode, recorder.Code)
+
+			if testCase.responseBody != "" {
+				require.Equal(t, testCase.responseBody, recorder.Body.String())
+			}
+		})
+	}
+}
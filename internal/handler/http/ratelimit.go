@@ -0,0 +1,113 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/b0shka/backend/internal/config"
+	"github.com/b0shka/backend/internal/domain"
+	"github.com/b0shka/backend/pkg/clientinfo"
+	"github.com/b0shka/backend/pkg/limiter"
+	"github.com/b0shka/backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	rateLimitLimitHeader     = "RateLimit-Limit"
+	rateLimitRemainingHeader = "RateLimit-Remaining"
+	rateLimitResetHeader     = "RateLimit-Reset"
+
+	// maxRateLimitBodySize bounds how much of the body is read to find the
+	// email, the requests keyed by email carry only a few fields.
+	maxRateLimitBodySize = 1 << 16
+)
+
+// rateLimit applies the policy to the requests of a route group under the
+// given name, so that groups sharing a key do not share a budget. A policy
+// without requests is disabled, and the request is let through when the
+// limiter fails, rate limiting must not take the api down with it.
+func (h *Handler) rateLimit(name string, policy config.RateLimitPolicy) gin.HandlerFunc {
+	if h.limiter == nil || policy.Requests <= 0 || policy.Period <= 0 {
+		return func(c *gin.Context) {}
+	}
+
+	limit := limiter.Limit{
+		Requests: policy.Requests,
+		Period:   policy.Period,
+	}
+
+	return func(c *gin.Context) {
+		key := name + ":" + rateLimitKey(c, policy.Key)
+
+		res, err := h.limiter.Allow(c, key, limit)
+		if err != nil {
+			logger.FromContext(c).Error("failed to apply rate limit", "policy", name, logger.Err(err))
+
+			return
+		}
+
+		c.Header(rateLimitLimitHeader, strconv.Itoa(res.Limit))
+		c.Header(rateLimitRemainingHeader, strconv.Itoa(res.Remaining))
+		c.Header(rateLimitResetHeader, strconv.Itoa(ceilSeconds(res.ResetAfter)))
+
+		if !res.Allowed {
+			newErrorResponse(c, domain.NewThrottledError(domain.ErrRateLimitExceeded, res.RetryAfter))
+
+			return
+		}
+	}
+}
+
+// rateLimitKey identifies the client of the request, falling back to the ip
+// when the user or the email is not known.
+func rateLimitKey(c *gin.Context, key string) string {
+	switch key {
+	case config.RateLimitKeyUser:
+		if payload, err := getUserPayload(c); err == nil {
+			return "user:" + payload.UserID.String()
+		}
+	case config.RateLimitKeyEmail:
+		if email := peekBodyEmail(c); email != "" {
+			sum := sha256.Sum256([]byte(email))
+
+			return "email:" + hex.EncodeToString(sum[:])
+		}
+	}
+
+	return "ip:" + clientinfo.FromContext(c).IP
+}
+
+// peekBodyEmail reads the email from the json body and puts the body back
+// for the handler to bind.
+func peekBodyEmail(c *gin.Context) string {
+	if c.Request.Body == nil {
+		return ""
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxRateLimitBodySize))
+	if err != nil {
+		return ""
+	}
+
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return ""
+	}
+
+	return strings.ToLower(strings.TrimSpace(req.Email))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/b0shka/backend/internal/config"
+	"github.com/b0shka/backend/pkg/limiter"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_rateLimit(t *testing.T) {
+	testTable := []struct {
+		name        string
+		policy      config.RateLimitPolicy
+		bodies      []string
+		statusCodes []int
+		remaining   []string
+	}{
+		{
+			name: "ip",
+			policy: config.RateLimitPolicy{
+				Requests: 2,
+				Period:   time.Minute,
+				Key:      config.RateLimitKeyIP,
+			},
+			bodies:      []string{`{}`, `{}`, `{}`},
+			statusCodes: []int{200, 200, 429},
+			remaining:   []string{"1", "0", "0"},
+		},
+		{
+			name: "email",
+			policy: config.RateLimitPolicy{
+				Requests: 1,
+				Period:   time.Minute,
+				Key:      config.RateLimitKeyEmail,
+			},
+			bodies: []string{
+				`{"email":"first@ya.ru"}`,
+				`{"email":"second@ya.ru"}`,
+				`{"email":"First@ya.ru"}`,
+			},
+			statusCodes: []int{200, 200, 429},
+			remaining:   []string{"0", "0", "0"},
+		},
+		{
+			name: "disabled",
+			policy: config.RateLimitPolicy{
+				Key: config.RateLimitKeyIP,
+			},
+			bodies:      []string{`{}`, `{}`},
+			statusCodes: []int{200, 200},
+			remaining:   []string{"", ""},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			handler := Handler{limiter: limiter.NewMemoryLimiter()}
+
+			router := newTestRouter()
+			router.POST("/limited", handler.rateLimit("test", testCase.policy), func(c *gin.Context) {
+				body, err := io.ReadAll(c.Request.Body)
+				require.NoError(t, err)
+
+				c.String(http.StatusOK, string(body))
+			})
+
+			for i, body := range testCase.bodies {
+				recorder := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodPost, "/limited", bytes.NewBufferString(body))
+
+				router.ServeHTTP(recorder, req)
+
+				require.Equal(t, testCase.statusCodes[i], recorder.Code)
+				require.Equal(t, testCase.remaining[i], recorder.Header().Get(rateLimitRemainingHeader))
+
+				if recorder.Code == http.StatusTooManyRequests {
+					require.NotEmpty(t, recorder.Header().Get("Retry-After"))
+					require.Contains(t, recorder.Body.String(), `"code":"rate_limit_exceeded"`)
+				} else {
+					require.Equal(t, body, recorder.Body.String())
+				}
+			}
+		})
+	}
+}
@@ -1,15 +1,184 @@
 package http
 
 import (
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/b0shka/backend/internal/domain"
+	"github.com/b0shka/backend/pkg/i18n"
 	"github.com/b0shka/backend/pkg/logger"
+	"github.com/b0shka/backend/pkg/requestid"
 	"github.com/gin-gonic/gin"
 )
 
-type response struct {
-	Message string `json:"message"`
+const (
+	problemContentType = "application/problem+json"
+	// problemType tells the clients that the problem has no documentation
+	// of its own, the code tells the problems apart.
+	problemType = "about:blank"
+
+	codeInternalError = "internal_error"
+)
+
+// problem is the RFC 7807 error body. The code is stable and meant for the
+// clients to branch on, the detail is meant for humans only.
+type problem struct {
+	Type       string `json:"type"`
+	Title      string `json:"title"`
+	Status     int    `json:"status"`
+	Detail     string `json:"detail,omitempty"`
+	Code       string `json:"code"`
+	RequestID  string `json:"request_id,omitempty"`
+	RetryAfter int    `json:"retry_after,omitempty"`
+}
+
+type problemMapping struct {
+	err    error
+	status int
+	code   string
+}
+
+// problemMappings maps the domain errors to the responses, the first match
+// wins. The errors that match none are internal and never shown.
+var problemMappings = []problemMapping{ //nolint: gochecknoglobals
+	{domain.ErrInvalidInput, http.StatusBadRequest, "invalid_input"},
+	{domain.ErrInvalidEmail, http.StatusBadRequest, "invalid_email"},
+	{domain.ErrIdentifier, http.StatusBadRequest, "invalid_identifier"},
+	{domain.ErrUnknownEventType, http.StatusBadRequest, "unknown_event_type"},
+	{domain.ErrInsecureWebhookURL, http.StatusBadRequest, "insecure_webhook_url"},
+	{domain.ErrUnknownTaskState, http.StatusBadRequest, "unknown_task_state"},
+	{domain.ErrUnsupportedLocale, http.StatusBadRequest, "unsupported_locale"},
+
+	{domain.ErrEmptyAuthHeader, http.StatusUnauthorized, "empty_authorization_header"},
+	{domain.ErrInvalidAuthHeaderFormat, http.StatusUnauthorized, "invalid_authorization_header"},
+	{domain.ErrUnsupportedAuthType, http.StatusUnauthorized, "unsupported_authorization_type"},
+	{domain.ErrInvalidToken, http.StatusUnauthorized, "invalid_token"},
+	{domain.ErrExpiredToken, http.StatusUnauthorized, "expired_token"},
+	{domain.ErrSecretCodeInvalid, http.StatusUnauthorized, "invalid_code"},
+	{domain.ErrSecretCodeExpired, http.StatusUnauthorized, "expired_code"},
+	{domain.ErrSessionBlocked, http.StatusUnauthorized, "session_blocked"},
+	{domain.ErrIncorrectSessionUser, http.StatusUnauthorized, "incorrect_session_user"},
+	{domain.ErrMismatchedSession, http.StatusUnauthorized, "mismatched_session"},
+	{domain.ErrEmptyAdminKey, http.StatusUnauthorized, "empty_admin_key"},
+	{domain.ErrInvalidAdminKey, http.StatusUnauthorized, "invalid_admin_key"},
+
+	{domain.ErrInvalidCSRFToken, http.StatusForbidden, "invalid_csrf_token"},
+
+	{domain.ErrRouteNotFound, http.StatusNotFound, "route_not_found"},
+	{domain.ErrUserNotFound, http.StatusNotFound, "user_not_found"},
+	{domain.ErrSessionNotFound, http.StatusNotFound, "session_not_found"},
+	{domain.ErrWebhookNotFound, http.StatusNotFound, "webhook_not_found"},
+	{domain.ErrWebhookDeliveryNotFound, http.StatusNotFound, "webhook_delivery_not_found"},
+	{domain.ErrUnknownQueue, http.StatusNotFound, "unknown_queue"},
+	{domain.ErrTaskNotFound, http.StatusNotFound, "task_not_found"},
+
+	{domain.ErrMethodNotAllowed, http.StatusMethodNotAllowed, "method_not_allowed"},
+
+	{domain.ErrRequestTooLarge, http.StatusRequestEntityTooLarge, "request_too_large"},
+
+	{domain.ErrSendCodeCooldown, http.StatusTooManyRequests, "send_code_cooldown"},
+	{domain.ErrSendCodeDailyLimit, http.StatusTooManyRequests, "send_code_daily_limit"},
+	{domain.ErrRateLimitExceeded, http.StatusTooManyRequests, "rate_limit_exceeded"},
+
+	{domain.ErrInspectorUnsupported, http.StatusNotImplemented, "inspector_unsupported"},
+}
+
+// newErrorResponse aborts the request with the error, errorMiddleware turns
+// it into the response.
+func newErrorResponse(c *gin.Context, err error) {
+	c.Abort()
+	_ = c.Error(err)
+}
+
+// newInputErrorResponse keeps the binding error, that names the fields of the
+// request types, in the logs only.
+func newInputErrorResponse(c *gin.Context, err error) {
+	logger.FromContext(c).Debug("failed to bind request", logger.Err(err))
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		newErrorResponse(c, domain.ErrRequestTooLarge)
+
+		return
+	}
+
+	newErrorResponse(c, domain.ErrInvalidInput)
+}
+
+// errorMiddleware writes the last error of the request as a problem. The
+// internal errors are logged and answered with a generic 500, so that the
+// SQL and the driver errors never reach the clients. The detail is
+// translated to the locale of the request when the bundle has the code.
+func errorMiddleware(bundle *i18n.Bundle) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		res := newProblem(err)
+		res.RequestID = requestid.FromContext(c)
+
+		level := slog.LevelWarn
+		if res.Status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		// the key is not "code", the values of which are redacted as the secret
+		// codes.
+		logger.FromContext(c).Log(c, level, "request failed", "status", res.Status, "problem_code", res.Code, logger.Err(err))
+
+		if bundle != nil {
+			locale := bundle.Match(i18n.LocaleFromContext(c))
+			if detail, ok := bundle.Lookup(locale, "errors."+res.Code); ok {
+				res.Detail = detail
+			}
+
+			c.Header("Content-Language", locale)
+		}
+
+		if res.RetryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(res.RetryAfter))
+		}
+
+		c.Header("Content-Type", problemContentType)
+		c.AbortWithStatusJSON(res.Status, res)
+	}
 }
 
-func newResponse(c *gin.Context, statusCode int, message string) {
-	logger.Error(message)
-	c.AbortWithStatusJSON(statusCode, response{message})
+func newProblem(err error) problem {
+	for _, mapping := range problemMappings {
+		if !errors.Is(err, mapping.err) {
+			continue
+		}
+
+		res := problem{
+			Type:   problemType,
+			Title:  http.StatusText(mapping.status),
+			Status: mapping.status,
+			Detail: err.Error(),
+			Code:   mapping.code,
+		}
+
+		// the clients are told in seconds how long to wait before retrying,
+		// both in the Retry-After header and in the body.
+		var throttledErr *domain.ThrottledError
+		if errors.As(err, &throttledErr) {
+			res.RetryAfter = int(math.Ceil(throttledErr.RetryAfter.Seconds()))
+		}
+
+		return res
+	}
+
+	return problem{
+		Type:   problemType,
+		Title:  http.StatusText(http.StatusInternalServerError),
+		Status: http.StatusInternalServerError,
+		Code:   codeInternalError,
+	}
 }
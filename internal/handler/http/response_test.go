@@ -0,0 +1,102 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/b0shka/backend/internal/config"
+	"github.com/b0shka/backend/internal/domain"
+	"github.com/b0shka/backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		statusCode int
+		body       string
+		retryAfter string
+	}{
+		{
+			name:       "domain error",
+			err:        fmt.Errorf("%w: %s", domain.ErrUnknownQueue, "mail"),
+			statusCode: http.StatusNotFound,
+			body: `{"type":"about:blank","title":"Not Found","status":404,` +
+				`"detail":"unknown task queue: mail","code":"unknown_queue"}`,
+		},
+		{
+			name:       "throttled",
+			err:        domain.NewThrottledError(domain.ErrSendCodeDailyLimit, 90*time.Second),
+			statusCode: http.StatusTooManyRequests,
+			body: `{"type":"about:blank","title":"Too Many Requests","status":429,` +
+				`"detail":"daily limit of sent codes is exceeded, retry after 1m30s",` +
+				`"code":"send_code_daily_limit","retry_after":90}`,
+			retryAfter: "90",
+		},
+		{
+			name: "internal error",
+			err: fmt.Errorf(
+				"SQL Error: relation \"users\" does not exist, Detail: , Where: , Code: 42P01, SQLState: 42P01",
+			),
+			statusCode: http.StatusInternalServerError,
+			body:       `{"type":"about:blank","title":"Internal Server Error","status":500,"code":"internal_error"}`,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			router := newTestRouter()
+			router.GET("/error", func(c *gin.Context) {
+				newErrorResponse(c, testCase.err)
+			})
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/error", nil))
+
+			require.Equal(t, testCase.statusCode, recorder.Code)
+			require.Equal(t, problemContentType, recorder.Header().Get("Content-Type"))
+			require.Equal(t, testCase.retryAfter, recorder.Header().Get("Retry-After"))
+			require.JSONEq(t, testCase.body, recorder.Body.String())
+		})
+	}
+}
+
+func TestErrorMiddleware_Written(t *testing.T) {
+	router := newTestRouter()
+	router.GET("/written", func(c *gin.Context) {
+		c.String(http.StatusAccepted, "accepted")
+		_ = c.Error(domain.ErrInvalidInput)
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/written", nil))
+
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+	require.Equal(t, "accepted", recorder.Body.String())
+}
+
+func TestErrorMiddleware_Log(t *testing.T) {
+	var buf bytes.Buffer
+
+	log, err := logger.New(&buf, config.LoggerConfig{Level: "debug"}, config.EnvProd)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.ContextWithFallback = true
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(logger.WithContext(c.Request.Context(), log))
+	}, errorMiddleware(nil))
+	router.GET("/error", func(c *gin.Context) {
+		newErrorResponse(c, domain.ErrUserNotFound)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/error", nil))
+
+	require.Contains(t, buf.String(), `"problem_code":"user_not_found"`)
+}
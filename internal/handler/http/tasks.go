@@ -0,0 +1,217 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultTasksPageSize = 30
+)
+
+func (h *Handler) initTasksRoutes(admin *gin.RouterGroup) {
+	queues := admin.Group("/queues")
+	{
+		queues.GET("", h.listQueues)
+		queues.POST("/:queue/pause", h.pauseQueue)
+		queues.POST("/:queue/resume", h.resumeQueue)
+		queues.GET("/:queue/tasks", h.listTasks)
+		queues.POST("/:queue/tasks/:task_id/run", h.runTask)
+		queues.DELETE("/:queue/tasks/:task_id", h.deleteTask)
+	}
+}
+
+type QueueResponse struct {
+	Name      string `json:"name"`
+	Size      int    `json:"size"`
+	Pending   int    `json:"pending"`
+	Active    int    `json:"active"`
+	Scheduled int    `json:"scheduled"`
+	Retry     int    `json:"retry"`
+	Archived  int    `json:"archived"`
+	Processed int    `json:"processed"`
+	Failed    int    `json:"failed"`
+	Paused    bool   `json:"paused"`
+}
+
+type ListTasksRequest struct {
+	State    string `form:"state" binding:"omitempty,oneof=archived retry"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+type TaskResponse struct {
+	ID            string    `json:"id"`
+	Queue         string    `json:"queue"`
+	Type          string    `json:"type"`
+	State         string    `json:"state"`
+	Payload       any       `json:"payload"`
+	MaxRetry      int       `json:"max_retry"`
+	Retried       int       `json:"retried"`
+	LastError     string    `json:"last_error"`
+	LastFailedAt  time.Time `json:"last_failed_at"`
+	NextProcessAt time.Time `json:"next_process_at"`
+}
+
+// @Summary		List Queues
+// @Security		AdminAuth
+// @Tags			admin
+// @Description	list worker queues with their sizes
+// @ModuleID		listQueues
+// @Accept			json
+// @Produce		json
+// @Success		200		{array}		QueueResponse
+// @Failure		401		{object}	problem
+// @Failure		500,501	{object}	problem
+// @Failure		default	{object}	problem
+// @Router			/admin/queues [get]
+func (h *Handler) listQueues(c *gin.Context) {
+	queues, err := h.services.Tasks.ListQueues(c)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	res := make([]QueueResponse, 0, len(queues))
+	for _, queue := range queues {
+		res = append(res, NewQueueResponse(queue))
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary		List Tasks
+// @Security		AdminAuth
+// @Tags			admin
+// @Description	browse archived or retry tasks of a queue, emails and codes are redacted
+// @ModuleID		listTasks
+// @Accept			json
+// @Produce		json
+// @Param			queue		path		string	true	"queue name"
+// @Param			state		query		string	false	"task state"	Enums(archived, retry)
+// @Param			page		query		int		false	"page number"
+// @Param			page_size	query		int		false	"page size"
+// @Success		200			{array}		TaskResponse
+// @Failure		400,401,404	{object}	problem
+// @Failure		500,501		{object}	problem
+// @Failure		default		{object}	problem
+// @Router			/admin/queues/{queue}/tasks [get]
+func (h *Handler) listTasks(c *gin.Context) {
+	var req ListTasksRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		newInputErrorResponse(c, err)
+
+		return
+	}
+
+	tasks, err := h.services.Tasks.ListTasks(c, NewListTasksInput(c.Param("queue"), req))
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	res := make([]TaskResponse, 0, len(tasks))
+	for _, task := range tasks {
+		res = append(res, NewTaskResponse(task))
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary		Run Task
+// @Security		AdminAuth
+// @Tags			admin
+// @Description	replay an archived or retry task right away
+// @ModuleID		runTask
+// @Accept			json
+// @Produce		json
+// @Param			queue		path		string	true	"queue name"
+// @Param			task_id		path		string	true	"task id"
+// @Success		200			{string}	string	"ok"
+// @Failure		401,404		{object}	problem
+// @Failure		500,501		{object}	problem
+// @Failure		default		{object}	problem
+// @Router			/admin/queues/{queue}/tasks/{task_id}/run [post]
+func (h *Handler) runTask(c *gin.Context) {
+	if err := h.services.Tasks.RunTask(c, c.Param("queue"), c.Param("task_id")); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// @Summary		Delete Task
+// @Security		AdminAuth
+// @Tags			admin
+// @Description	delete a task from a queue
+// @ModuleID		deleteTask
+// @Accept			json
+// @Produce		json
+// @Param			queue		path		string	true	"queue name"
+// @Param			task_id		path		string	true	"task id"
+// @Success		200			{string}	string	"ok"
+// @Failure		401,404		{object}	problem
+// @Failure		500,501		{object}	problem
+// @Failure		default		{object}	problem
+// @Router			/admin/queues/{queue}/tasks/{task_id} [delete]
+func (h *Handler) deleteTask(c *gin.Context) {
+	if err := h.services.Tasks.DeleteTask(c, c.Param("queue"), c.Param("task_id")); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// @Summary		Pause Queue
+// @Security		AdminAuth
+// @Tags			admin
+// @Description	stop processing the tasks of a queue
+// @ModuleID		pauseQueue
+// @Accept			json
+// @Produce		json
+// @Param			queue		path		string	true	"queue name"
+// @Success		200			{string}	string	"ok"
+// @Failure		401,404		{object}	problem
+// @Failure		500,501		{object}	problem
+// @Failure		default		{object}	problem
+// @Router			/admin/queues/{queue}/pause [post]
+func (h *Handler) pauseQueue(c *gin.Context) {
+	if err := h.services.Tasks.PauseQueue(c, c.Param("queue")); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// @Summary		Resume Queue
+// @Security		AdminAuth
+// @Tags			admin
+// @Description	resume processing the tasks of a paused queue
+// @ModuleID		resumeQueue
+// @Accept			json
+// @Produce		json
+// @Param			queue		path		string	true	"queue name"
+// @Success		200			{string}	string	"ok"
+// @Failure		401,404		{object}	problem
+// @Failure		500,501		{object}	problem
+// @Failure		default		{object}	problem
+// @Router			/admin/queues/{queue}/resume [post]
+func (h *Handler) resumeQueue(c *gin.Context) {
+	if err := h.services.Tasks.ResumeQueue(c, c.Param("queue")); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/b0shka/backend/internal/domain"
+	domain_task "github.com/b0shka/backend/internal/domain/task"
+	"github.com/b0shka/backend/internal/service"
+	mock_service "github.com/b0shka/backend/internal/service/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_listTasks(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockTasks)
+
+	task := domain_task.Task{
+		ID:      "task_id",
+		Queue:   "critical",
+		Type:    "task:send_verify_email",
+		State:   domain_task.StateArchived,
+		Payload: json.RawMessage(`{"email":"j***@example.com","secret_code":"[REDACTED]"}`),
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		mockBehavior mockBehavior
+		statusCode   int
+	}{
+		{
+			name:  "ok with defaults",
+			query: "",
+			mockBehavior: func(s *mock_service.MockTasks) {
+				s.EXPECT().
+					ListTasks(gomock.Any(), domain_task.NewListTasksInput("critical", domain_task.StateArchived, 1, 30)).
+					Return([]domain_task.Task{task}, nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "ok retry tasks",
+			query: "?state=retry&page=2&page_size=10",
+			mockBehavior: func(s *mock_service.MockTasks) {
+				s.EXPECT().
+					ListTasks(gomock.Any(), domain_task.NewListTasksInput("critical", domain_task.StateRetry, 2, 10)).
+					Return([]domain_task.Task{}, nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "invalid state",
+			query: "?state=pending",
+			mockBehavior: func(s *mock_service.MockTasks) {
+				s.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "unknown queue",
+			query: "",
+			mockBehavior: func(s *mock_service.MockTasks) {
+				s.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Return(nil, domain.ErrUnknownQueue)
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:  "unsupported backend",
+			query: "",
+			mockBehavior: func(s *mock_service.MockTasks) {
+				s.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Return(nil, domain.ErrInspectorUnsupported)
+			},
+			statusCode: http.StatusNotImplemented,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			defer mockCtl.Finish()
+
+			tasksService := mock_service.NewMockTasks(mockCtl)
+			testCase.mockBehavior(tasksService)
+
+			services := &service.Services{Tasks: tasksService}
+			handler := Handler{services: services}
+
+			router := newTestRouter()
+			router.GET("/queues/:queue/tasks", handler.listTasks)
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/queues/critical/tasks"+testCase.query, nil)
+
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, testCase.statusCode, recorder.Code)
+		})
+	}
+}
@@ -3,21 +3,19 @@ package http
 import (
 	"bytes"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/b0shka/backend/internal/config"
 	"github.com/b0shka/backend/internal/domain"
 	domain_user "github.com/b0shka/backend/internal/domain/user"
-	repository "github.com/b0shka/backend/internal/repository/postgresql/sqlc"
 	"github.com/b0shka/backend/internal/service"
 	mock_service "github.com/b0shka/backend/internal/service/mocks"
 	"github.com/b0shka/backend/pkg/auth"
 	"github.com/b0shka/backend/pkg/identity"
 	"github.com/b0shka/backend/pkg/utils"
-	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -91,7 +89,7 @@ func TestHandler_getUserById(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 				require.Equal(
 					t,
-					`{"message":"empty authorization header"}`,
+					problemBody(t, domain.ErrEmptyAuthHeader),
 					recorder.Body.String(),
 				)
 			},
@@ -105,13 +103,13 @@ func TestHandler_getUserById(t *testing.T) {
 			mockBehavior: func(s *mock_service.MockUsers, userID uuid.UUID) {
 				s.EXPECT().
 					GetByID(gomock.Any(), userID).
-					Return(domain_user.User{}, repository.ErrRecordNotFound)
+					Return(domain_user.User{}, domain.ErrUserNotFound)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
 				require.Equal(
 					t,
-					fmt.Sprintf(`{"message":"%s"}`, domain.ErrUserNotFound),
+					problemBody(t, domain.ErrUserNotFound),
 					recorder.Body.String(),
 				)
 			},
@@ -131,7 +129,7 @@ func TestHandler_getUserById(t *testing.T) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 				require.Equal(
 					t,
-					fmt.Sprintf(`{"message":"%s"}`, ErrInternalServerError),
+					problemBody(t, ErrInternalServerError),
 					recorder.Body.String(),
 				)
 			},
@@ -153,27 +151,21 @@ func TestHandler_getUserById(t *testing.T) {
 			testCase.mockBehavior(usersService, testCase.userID)
 
 			services := &service.Services{Users: usersService}
-			handler := Handler{services: services}
+			handler := newTestGatewayHandler(t, services, tokenManager)
 
-			router := gin.Default()
-			router.GET(
-				"/",
-				userIdentity(tokenManager),
-				handler.getUserByID,
-			)
+			router := newTestRouter()
+			handler.initUsersRoutes(router.Group("/api/v1"), config.RateLimitPolicy{}, nil)
 
 			recorder := httptest.NewRecorder()
 			req := httptest.NewRequest(
 				http.MethodGet,
-				"/",
+				"/api/v1/users/",
 				nil,
 			)
 
 			testCase.setupAuth(t, req, tokenManager)
 			router.ServeHTTP(recorder, req)
-
-			// require.Equal(t, testCase.statusCode, recorder.Code)
-			// require.Equal(t, testCase.responseBody, recorder.Body.String())
+			testCase.checkResponse(recorder)
 		})
 	}
 }
@@ -212,7 +204,7 @@ func TestHandler_deleteUser(t *testing.T) {
 				s.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(0)
 			},
 			statusCode:   401,
-			responseBody: `{"message":"empty authorization header"}`,
+			responseBody: problemBody(t, domain.ErrEmptyAuthHeader),
 		},
 		{
 			name:   "user not found",
@@ -221,10 +213,10 @@ func TestHandler_deleteUser(t *testing.T) {
 				addAuthorizationHeader(t, request, tokenManager, authorizationTypeBearer, userID, time.Minute)
 			},
 			mockBehavior: func(s *mock_service.MockUsers, userID uuid.UUID) {
-				s.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(repository.ErrRecordNotFound)
+				s.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(domain.ErrUserNotFound)
 			},
 			statusCode:   404,
-			responseBody: fmt.Sprintf(`{"message":"%s"}`, domain.ErrUserNotFound),
+			responseBody: problemBody(t, domain.ErrUserNotFound),
 		},
 		{
 			name:   "error delete user",
@@ -236,7 +228,7 @@ func TestHandler_deleteUser(t *testing.T) {
 				s.EXPECT().Delete(gomock.Any(), userID).Return(ErrInternalServerError)
 			},
 			statusCode:   500,
-			responseBody: fmt.Sprintf(`{"message":"%s"}`, ErrInternalServerError),
+			responseBody: problemBody(t, ErrInternalServerError),
 		},
 	}
 
@@ -255,19 +247,15 @@ func TestHandler_deleteUser(t *testing.T) {
 			testCase.mockBehavior(usersService, testCase.userID)
 
 			services := &service.Services{Users: usersService}
-			handler := Handler{services: services}
+			handler := newTestGatewayHandler(t, services, tokenManager)
 
-			router := gin.Default()
-			router.GET(
-				"/delete",
-				userIdentity(tokenManager),
-				handler.deleteUser,
-			)
+			router := newTestRouter()
+			handler.initUsersRoutes(router.Group("/api/v1"), config.RateLimitPolicy{}, nil)
 
 			recorder := httptest.NewRecorder()
 			req := httptest.NewRequest(
-				http.MethodGet,
-				"/delete",
+				http.MethodDelete,
+				"/api/v1/users/",
 				nil,
 			)
 
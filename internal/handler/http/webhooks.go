@@ -0,0 +1,226 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/b0shka/backend/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) initWebhooksRoutes(admin *gin.RouterGroup) {
+	webhooks := admin.Group("/webhooks")
+	{
+		webhooks.POST("", h.createWebhook)
+		webhooks.GET("", h.listWebhooks)
+		webhooks.DELETE("/:id", h.deleteWebhook)
+		webhooks.GET("/:id/deliveries", h.listWebhookDeliveries)
+		webhooks.POST("/:id/deliveries/:delivery_id/redeliver", h.redeliverWebhook)
+	}
+}
+
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" binding:"required,url"`
+	Secret     string   `json:"secret" binding:"required,min=16"`
+	EventTypes []string `json:"event_types" binding:"required,min=1"`
+}
+
+type WebhookResponse struct {
+	ID           uuid.UUID `json:"id"`
+	URL          string    `json:"url"`
+	EventTypes   []string  `json:"event_types"`
+	IsActive     bool      `json:"is_active"`
+	FailureCount int       `json:"failure_count"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type WebhookAttemptResponse struct {
+	ResponseCode int       `json:"response_code"`
+	Error        string    `json:"error,omitempty"`
+	DurationMs   int64     `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID        uuid.UUID                `json:"id"`
+	EventID   uuid.UUID                `json:"event_id"`
+	EventType string                   `json:"event_type"`
+	Status    string                   `json:"status"`
+	Attempts  []WebhookAttemptResponse `json:"attempts"`
+	CreatedAt time.Time                `json:"created_at"`
+	UpdatedAt time.Time                `json:"updated_at"`
+	RequestID string                   `json:"request_id,omitempty"`
+}
+
+// @Summary		Create Webhook
+// @Security		AdminAuth
+// @Tags			admin
+// @Description	subscribe an endpoint to account events
+// @ModuleID		createWebhook
+// @Accept			json
+// @Produce		json
+// @Param			input	body		CreateWebhookRequest	true	"webhook info"
+// @Success		201		{object}	WebhookResponse
+// @Failure		400,401	{object}	problem
+// @Failure		500		{object}	problem
+// @Failure		default	{object}	problem
+// @Router			/admin/webhooks [post]
+func (h *Handler) createWebhook(c *gin.Context) {
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		newInputErrorResponse(c, err)
+
+		return
+	}
+
+	subscription, err := h.services.Webhooks.Create(c, NewCreateSubscriptionInput(req))
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewWebhookResponse(subscription))
+}
+
+// @Summary		List Webhooks
+// @Security		AdminAuth
+// @Tags			admin
+// @Description	list webhook subscriptions
+// @ModuleID		listWebhooks
+// @Accept			json
+// @Produce		json
+// @Success		200		{array}		WebhookResponse
+// @Failure		401		{object}	problem
+// @Failure		500		{object}	problem
+// @Failure		default	{object}	problem
+// @Router			/admin/webhooks [get]
+func (h *Handler) listWebhooks(c *gin.Context) {
+	subscriptions, err := h.services.Webhooks.List(c)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	res := make([]WebhookResponse, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		res = append(res, NewWebhookResponse(subscription))
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary		Delete Webhook
+// @Security		AdminAuth
+// @Tags			admin
+// @Description	delete webhook subscription
+// @ModuleID		deleteWebhook
+// @Accept			json
+// @Produce		json
+// @Param			id		path		string	true	"subscription id"
+// @Success		200		{string}	string	"ok"
+// @Failure		400,401,404	{object}	problem
+// @Failure		500		{object}	problem
+// @Failure		default	{object}	problem
+// @Router			/admin/webhooks/{id} [delete]
+func (h *Handler) deleteWebhook(c *gin.Context) {
+	id, err := parseIDFromPath(c, "id")
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	if err := h.services.Webhooks.Delete(c, id); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// @Summary		List Webhook Deliveries
+// @Security		AdminAuth
+// @Tags			admin
+// @Description	list recent deliveries of a webhook subscription with their attempts
+// @ModuleID		listWebhookDeliveries
+// @Accept			json
+// @Produce		json
+// @Param			id		path		string	true	"subscription id"
+// @Success		200		{array}		WebhookDeliveryResponse
+// @Failure		400,401,404	{object}	problem
+// @Failure		500		{object}	problem
+// @Failure		default	{object}	problem
+// @Router			/admin/webhooks/{id}/deliveries [get]
+func (h *Handler) listWebhookDeliveries(c *gin.Context) {
+	id, err := parseIDFromPath(c, "id")
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	deliveries, err := h.services.Webhooks.ListDeliveries(c, id)
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	res := make([]WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		res = append(res, NewWebhookDeliveryResponse(delivery))
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary		Redeliver Webhook
+// @Security		AdminAuth
+// @Tags			admin
+// @Description	send a webhook delivery again
+// @ModuleID		redeliverWebhook
+// @Accept			json
+// @Produce		json
+// @Param			id			path		string	true	"subscription id"
+// @Param			delivery_id	path		string	true	"delivery id"
+// @Success		202			{string}	string	"accepted"
+// @Failure		400,401,404	{object}	problem
+// @Failure		500			{object}	problem
+// @Failure		default		{object}	problem
+// @Router			/admin/webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (h *Handler) redeliverWebhook(c *gin.Context) {
+	id, err := parseIDFromPath(c, "id")
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	deliveryID, err := parseIDFromPath(c, "delivery_id")
+	if err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	if err := h.services.Webhooks.Redeliver(c, id, deliveryID); err != nil {
+		newErrorResponse(c, err)
+
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+func parseIDFromPath(c *gin.Context, param string) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		return uuid.UUID{}, domain.ErrIdentifier
+	}
+
+	return id, nil
+}
@@ -0,0 +1,214 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/b0shka/backend/internal/domain"
+	domain_webhook "github.com/b0shka/backend/internal/domain/webhook"
+	"github.com/b0shka/backend/internal/event"
+	"github.com/b0shka/backend/internal/service"
+	mock_service "github.com/b0shka/backend/internal/service/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+const testAdminAPIKey = "admin_api_key"
+
+func TestHandler_createWebhook(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockWebhooks, inp domain_webhook.CreateSubscriptionInput)
+
+	subscription := domain_webhook.Subscription{
+		ID:         uuid.New(),
+		URL:        "https://example.com/hook",
+		Secret:     "0123456789abcdef",
+		EventTypes: []string{event.TypeUserCreated},
+		IsActive:   true,
+		CreatedAt:  time.Now(),
+	}
+
+	tests := []struct {
+		name         string
+		adminKey     string
+		body         string
+		input        domain_webhook.CreateSubscriptionInput
+		mockBehavior mockBehavior
+		statusCode   int
+	}{
+		{
+			name:     "ok",
+			adminKey: testAdminAPIKey,
+			body:     `{"url":"https://example.com/hook","secret":"0123456789abcdef","event_types":["user.created"]}`,
+			input: domain_webhook.NewCreateSubscriptionInput(
+				subscription.URL,
+				subscription.Secret,
+				subscription.EventTypes,
+			),
+			mockBehavior: func(s *mock_service.MockWebhooks, inp domain_webhook.CreateSubscriptionInput) {
+				s.EXPECT().Create(gomock.Any(), inp).Return(subscription, nil)
+			},
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:     "unknown event type",
+			adminKey: testAdminAPIKey,
+			body:     `{"url":"https://example.com/hook","secret":"0123456789abcdef","event_types":["user.unknown"]}`,
+			input: domain_webhook.NewCreateSubscriptionInput(
+				subscription.URL,
+				subscription.Secret,
+				[]string{"user.unknown"},
+			),
+			mockBehavior: func(s *mock_service.MockWebhooks, inp domain_webhook.CreateSubscriptionInput) {
+				s.EXPECT().Create(gomock.Any(), inp).Return(domain_webhook.Subscription{}, domain.ErrUnknownEventType)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid input",
+			adminKey: testAdminAPIKey,
+			body:     `{"url":"not a url","secret":"short","event_types":[]}`,
+			mockBehavior: func(s *mock_service.MockWebhooks, inp domain_webhook.CreateSubscriptionInput) {
+				s.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid admin key",
+			adminKey: "wrong",
+			body:     `{"url":"https://example.com/hook","secret":"0123456789abcdef","event_types":["user.created"]}`,
+			mockBehavior: func(s *mock_service.MockWebhooks, inp domain_webhook.CreateSubscriptionInput) {
+				s.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+			},
+			statusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			defer mockCtl.Finish()
+
+			webhooksService := mock_service.NewMockWebhooks(mockCtl)
+			testCase.mockBehavior(webhooksService, testCase.input)
+
+			services := &service.Services{Webhooks: webhooksService}
+			handler := Handler{services: services}
+
+			router := newTestRouter()
+			router.POST(
+				"/",
+				adminIdentity(testAdminAPIKey),
+				handler.createWebhook,
+			)
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/",
+				bytes.NewBufferString(testCase.body),
+			)
+			req.Header.Set(adminKeyHeaderKey, testCase.adminKey)
+
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, testCase.statusCode, recorder.Code)
+
+			if testCase.statusCode == http.StatusCreated {
+				require.NotContains(t, recorder.Body.String(), subscription.Secret)
+
+				var res WebhookResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, subscription.ID, res.ID)
+				require.Equal(t, subscription.EventTypes, res.EventTypes)
+			}
+		})
+	}
+}
+
+func TestHandler_listWebhooks(t *testing.T) {
+	tests := []struct {
+		name         string
+		apiKey       string
+		adminKey     string
+		mockBehavior func(s *mock_service.MockWebhooks)
+		statusCode   int
+		responseBody string
+	}{
+		{
+			name:     "ok",
+			apiKey:   testAdminAPIKey,
+			adminKey: testAdminAPIKey,
+			mockBehavior: func(s *mock_service.MockWebhooks) {
+				s.EXPECT().List(gomock.Any()).Return([]domain_webhook.Subscription{{ID: uuid.New()}}, nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:     "empty admin key",
+			apiKey:   testAdminAPIKey,
+			adminKey: "",
+			mockBehavior: func(s *mock_service.MockWebhooks) {
+				s.EXPECT().List(gomock.Any()).Times(0)
+			},
+			statusCode:   http.StatusUnauthorized,
+			responseBody: problemBody(t, domain.ErrEmptyAdminKey),
+		},
+		{
+			name:     "admin api disabled",
+			apiKey:   "",
+			adminKey: testAdminAPIKey,
+			mockBehavior: func(s *mock_service.MockWebhooks) {
+				s.EXPECT().List(gomock.Any()).Times(0)
+			},
+			statusCode:   http.StatusUnauthorized,
+			responseBody: problemBody(t, domain.ErrInvalidAdminKey),
+		},
+		{
+			name:     "error list webhooks",
+			apiKey:   testAdminAPIKey,
+			adminKey: testAdminAPIKey,
+			mockBehavior: func(s *mock_service.MockWebhooks) {
+				s.EXPECT().List(gomock.Any()).Return(nil, ErrInternalServerError)
+			},
+			statusCode:   http.StatusInternalServerError,
+			responseBody: problemBody(t, ErrInternalServerError),
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			defer mockCtl.Finish()
+
+			webhooksService := mock_service.NewMockWebhooks(mockCtl)
+			testCase.mockBehavior(webhooksService)
+
+			services := &service.Services{Webhooks: webhooksService}
+			handler := Handler{services: services}
+
+			router := newTestRouter()
+			router.GET(
+				"/",
+				adminIdentity(testCase.apiKey),
+				handler.listWebhooks,
+			)
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(adminKeyHeaderKey, testCase.adminKey)
+
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, testCase.statusCode, recorder.Code)
+
+			if testCase.responseBody != "" {
+				require.Equal(t, testCase.responseBody, recorder.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	namespace = "backend"
+
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+
+	EmailStatusSent   = "sent"
+	EmailStatusFailed = "failed"
+
+	AuthOperationSendCode = "send_code"
+	AuthOperationSignIn   = "sign_in"
+	AuthOperationRefresh  = "refresh"
+)
+
+var (
+	registry = newRegistry() //nolint: gochecknoglobals
+
+	httpRequestsTotal = prometheus.NewCounterVec( //nolint: gochecknoglobals
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of handled HTTP requests.",
+		},
+		[]string{"method", "route", "status"},
+	)
+	httpRequestDuration = prometheus.NewHistogramVec( //nolint: gochecknoglobals
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of handled HTTP requests.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"method", "route", "status"},
+	)
+
+	tasksProcessedTotal = prometheus.NewCounterVec( //nolint: gochecknoglobals
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "worker",
+			Name:      "tasks_processed_total",
+			Help:      "Number of processed tasks by outcome.",
+		},
+		[]string{"type", "status"},
+	)
+	taskDuration = prometheus.NewHistogramVec( //nolint: gochecknoglobals
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "worker",
+			Name:      "task_duration_seconds",
+			Help:      "Duration of processed tasks.",
+			Buckets:   []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+		},
+		[]string{"type", "status"},
+	)
+
+	emailsTotal = prometheus.NewCounterVec( //nolint: gochecknoglobals
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "email",
+			Name:      "messages_total",
+			Help:      "Number of emails sent and failed to send.",
+		},
+		[]string{"template", "status"},
+	)
+
+	authOutcomesTotal = prometheus.NewCounterVec( //nolint: gochecknoglobals
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "auth",
+			Name:      "outcomes_total",
+			Help:      "Number of authentication attempts by operation and outcome.",
+		},
+		[]string{"operation", "outcome"},
+	)
+)
+
+func newRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestsTotal,
+		httpRequestDuration,
+		tasksProcessedTotal,
+		taskDuration,
+		emailsTotal,
+		authOutcomesTotal,
+	)
+
+	return registry
+}
+
+// Handler serves the metrics of the application in the prometheus format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// Register adds collectors of the application state, such as the database
+// pool or the task queues.
+func Register(cs ...prometheus.Collector) error {
+	for _, c := range cs {
+		if err := registry.Register(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ObserveHTTPRequest records a handled request, route is the matched route
+// pattern so that path parameters do not blow up the cardinality.
+func ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+
+	httpRequestsTotal.WithLabelValues(method, route, code).Inc()
+	httpRequestDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+func ObserveTask(taskType string, err error, duration time.Duration) {
+	status := StatusSuccess
+	if err != nil {
+		status = StatusFailure
+	}
+
+	tasksProcessedTotal.WithLabelValues(taskType, status).Inc()
+	taskDuration.WithLabelValues(taskType, status).Observe(duration.Seconds())
+}
+
+func ObserveEmail(template string, err error) {
+	status := EmailStatusSent
+	if err != nil {
+		status = EmailStatusFailed
+	}
+
+	emailsTotal.WithLabelValues(template, status).Inc()
+}
+
+func ObserveAuth(operation, outcome string) {
+	authOutcomesTotal.WithLabelValues(operation, outcome).Inc()
+}
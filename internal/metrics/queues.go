@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"log/slog"
+
+	domain_task "github.com/b0shka/backend/internal/domain/task"
+	"github.com/b0shka/backend/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type QueueLister interface {
+	Queues() ([]domain_task.Queue, error)
+}
+
+// QueueCollector exposes the depth of the task queues by state.
+type QueueCollector struct {
+	lister QueueLister
+
+	size      *prometheus.Desc
+	paused    *prometheus.Desc
+	processed *prometheus.Desc
+	failed    *prometheus.Desc
+}
+
+func NewQueueCollector(lister QueueLister) *QueueCollector {
+	return &QueueCollector{
+		lister: lister,
+		size: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "worker", "queue_size"),
+			"Number of tasks in the queue by state.",
+			[]string{"queue", "state"}, nil,
+		),
+		paused: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "worker", "queue_paused"),
+			"Whether the queue is paused.",
+			[]string{"queue"}, nil,
+		),
+		processed: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "worker", "queue_processed_today"),
+			"Number of tasks processed by the queue today.",
+			[]string{"queue"}, nil,
+		),
+		failed: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "worker", "queue_failed_today"),
+			"Number of tasks failed in the queue today.",
+			[]string{"queue"}, nil,
+		),
+	}
+}
+
+func (c *QueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.size
+	ch <- c.paused
+	ch <- c.processed
+	ch <- c.failed
+}
+
+// Collect skips the queue metrics when the broker is unavailable, the scrape
+// still succeeds with the rest of the metrics.
+func (c *QueueCollector) Collect(ch chan<- prometheus.Metric) {
+	queues, err := c.lister.Queues()
+	if err != nil {
+		slog.Error("failed to collect queue metrics", logger.Err(err))
+
+		return
+	}
+
+	for _, queue := range queues {
+		states := map[string]int{
+			"pending":   queue.Pending,
+			"active":    queue.Active,
+			"scheduled": queue.Scheduled,
+			"retry":     queue.Retry,
+			"archived":  queue.Archived,
+		}
+		for state, size := range states {
+			ch <- prometheus.MustNewConstMetric(c.size, prometheus.GaugeValue, float64(size), queue.Name, state)
+		}
+
+		paused := 0.0
+		if queue.Paused {
+			paused = 1
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.paused, prometheus.GaugeValue, paused, queue.Name)
+		ch <- prometheus.MustNewConstMetric(c.processed, prometheus.GaugeValue, float64(queue.Processed), queue.Name)
+		ch <- prometheus.MustNewConstMetric(c.failed, prometheus.GaugeValue, float64(queue.Failed), queue.Name)
+	}
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	domain_job "github.com/b0shka/backend/internal/domain/job"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type JobRunsRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewJobRunsRepo(db *pgxpool.Pool) *JobRunsRepo {
+	return &JobRunsRepo{
+		db: db,
+	}
+}
+
+type CreateJobRunParams struct {
+	ID          uuid.UUID `json:"id"`
+	Job         string    `json:"job"`
+	Target      string    `json:"target"`
+	RowsRemoved int64     `json:"rows_removed"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+}
+
+func (r *JobRunsRepo) Create(ctx context.Context, arg CreateJobRunParams) (domain_job.Run, error) {
+	q := `
+		INSERT INTO job_runs
+		    (id, job, target, rows_removed, started_at, finished_at)
+		VALUES
+			($1, $2, $3, $4, $5, $6)
+		RETURNING id, job, target, rows_removed, started_at, finished_at
+	`
+
+	var run domain_job.Run
+	if err := r.db.
+		QueryRow(
+			ctx,
+			q,
+			arg.ID,
+			arg.Job,
+			arg.Target,
+			arg.RowsRemoved,
+			arg.StartedAt,
+			arg.FinishedAt,
+		).
+		Scan(
+			&run.ID,
+			&run.Job,
+			&run.Target,
+			&run.RowsRemoved,
+			&run.StartedAt,
+			&run.FinishedAt,
+		); err != nil {
+		return domain_job.Run{}, err
+	}
+
+	return run, nil
+}
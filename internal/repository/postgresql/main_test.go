@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"log/slog"
 	"os"
 	"testing"
 
@@ -16,7 +17,7 @@ func TestMain(m *testing.M) {
 
 	postgreSQLClient, err := pgxpool.New(context.Background(), postgresSource)
 	if err != nil {
-		logger.Errorf("cannot to connect to database: %s", err)
+		slog.Error("cannot to connect to database", logger.Err(err))
 	}
 
 	// os.Setenv("APP_ENV", "local")
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "webhook_delivery_attempts" CASCADE;
+DROP TABLE IF EXISTS "webhook_deliveries" CASCADE;
+DROP TABLE IF EXISTS "webhook_subscriptions" CASCADE;
@@ -0,0 +1,38 @@
+CREATE TABLE "webhook_subscriptions" (
+  "id" UUID PRIMARY KEY,
+  "url" varchar NOT NULL,
+  "secret" varchar NOT NULL,
+  "event_types" varchar[] NOT NULL,
+  "is_active" boolean NOT NULL DEFAULT true,
+  "failure_count" integer NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "webhook_deliveries" (
+  "id" UUID PRIMARY KEY,
+  "subscription_id" UUID NOT NULL,
+  "event_id" UUID NOT NULL,
+  "event_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "attempts" integer NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "webhook_delivery_attempts" (
+  "id" UUID PRIMARY KEY,
+  "delivery_id" UUID NOT NULL,
+  "response_code" integer NOT NULL DEFAULT 0,
+  "error" varchar NOT NULL DEFAULT '',
+  "duration_ms" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "webhook_deliveries" ("subscription_id", "created_at");
+
+CREATE INDEX ON "webhook_delivery_attempts" ("delivery_id");
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("subscription_id") REFERENCES "webhook_subscriptions" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "webhook_delivery_attempts" ADD FOREIGN KEY ("delivery_id") REFERENCES "webhook_deliveries" ("id") ON DELETE CASCADE;
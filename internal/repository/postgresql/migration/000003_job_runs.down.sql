@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "sessions_expires_at_idx";
+DROP INDEX IF EXISTS "verify_emails_expires_at_idx";
+DROP TABLE IF EXISTS "job_runs";
@@ -0,0 +1,14 @@
+CREATE TABLE "job_runs" (
+  "id" UUID PRIMARY KEY,
+  "job" varchar NOT NULL,
+  "target" varchar NOT NULL,
+  "rows_removed" bigint NOT NULL DEFAULT 0,
+  "started_at" timestamptz NOT NULL,
+  "finished_at" timestamptz NOT NULL
+);
+
+CREATE INDEX ON "job_runs" ("job", "started_at");
+
+CREATE INDEX ON "verify_emails" ("expires_at");
+
+CREATE INDEX ON "sessions" ("expires_at");
@@ -0,0 +1,3 @@
+ALTER TABLE "webhook_deliveries" DROP COLUMN IF EXISTS "request_id";
+
+ALTER TABLE "sessions" DROP COLUMN IF EXISTS "request_id";
@@ -0,0 +1,3 @@
+ALTER TABLE "sessions" ADD COLUMN "request_id" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "webhook_deliveries" ADD COLUMN "request_id" varchar NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE "sessions" ADD COLUMN "device_id" varchar NOT NULL DEFAULT '';
@@ -7,9 +7,12 @@ package mock_repository
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	auth "github.com/b0shka/backend/internal/domain/auth"
+	job "github.com/b0shka/backend/internal/domain/job"
 	user "github.com/b0shka/backend/internal/domain/user"
+	webhook "github.com/b0shka/backend/internal/domain/webhook"
 	repository "github.com/b0shka/backend/internal/repository/postgresql"
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -81,6 +84,21 @@ func (mr *MockVerifyEmailsMockRecorder) DeleteByID(ctx, id interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockVerifyEmails)(nil).DeleteByID), ctx, id)
 }
 
+// DeleteExpired mocks base method.
+func (m *MockVerifyEmails) DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, before, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockVerifyEmailsMockRecorder) DeleteExpired(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockVerifyEmails)(nil).DeleteExpired), ctx, before, limit)
+}
+
 // Get mocks base method.
 func (m *MockVerifyEmails) Get(ctx context.Context, arg repository.GetVerifyEmailParams) (auth.VerifyEmail, error) {
 	m.ctrl.T.Helper()
@@ -148,6 +166,21 @@ func (mr *MockSessionsMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSessions)(nil).Delete), ctx, id)
 }
 
+// DeleteExpired mocks base method.
+func (m *MockSessions) DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, before, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockSessionsMockRecorder) DeleteExpired(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockSessions)(nil).DeleteExpired), ctx, before, limit)
+}
+
 // Get mocks base method.
 func (m *MockSessions) Get(ctx context.Context, id uuid.UUID) (auth.Session, error) {
 	m.ctrl.T.Helper()
@@ -244,3 +277,293 @@ func (mr *MockUsersMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUsers)(nil).GetByID), ctx, id)
 }
+
+// UpdateLocale mocks base method.
+func (m *MockUsers) UpdateLocale(ctx context.Context, id uuid.UUID, locale string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLocale", ctx, id, locale)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLocale indicates an expected call of UpdateLocale.
+func (mr *MockUsersMockRecorder) UpdateLocale(ctx, id, locale interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLocale", reflect.TypeOf((*MockUsers)(nil).UpdateLocale), ctx, id, locale)
+}
+
+// MockWebhookSubscriptions is a mock of WebhookSubscriptions interface.
+type MockWebhookSubscriptions struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookSubscriptionsMockRecorder
+}
+
+// MockWebhookSubscriptionsMockRecorder is the mock recorder for MockWebhookSubscriptions.
+type MockWebhookSubscriptionsMockRecorder struct {
+	mock *MockWebhookSubscriptions
+}
+
+// NewMockWebhookSubscriptions creates a new mock instance.
+func NewMockWebhookSubscriptions(ctrl *gomock.Controller) *MockWebhookSubscriptions {
+	mock := &MockWebhookSubscriptions{ctrl: ctrl}
+	mock.recorder = &MockWebhookSubscriptionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookSubscriptions) EXPECT() *MockWebhookSubscriptionsMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWebhookSubscriptions) Create(ctx context.Context, arg repository.CreateWebhookSubscriptionParams) (webhook.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, arg)
+	ret0, _ := ret[0].(webhook.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhookSubscriptionsMockRecorder) Create(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhookSubscriptions)(nil).Create), ctx, arg)
+}
+
+// Delete mocks base method.
+func (m *MockWebhookSubscriptions) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebhookSubscriptionsMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebhookSubscriptions)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockWebhookSubscriptions) Get(ctx context.Context, id uuid.UUID) (webhook.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(webhook.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockWebhookSubscriptionsMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWebhookSubscriptions)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockWebhookSubscriptions) List(ctx context.Context) ([]webhook.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]webhook.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockWebhookSubscriptionsMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWebhookSubscriptions)(nil).List), ctx)
+}
+
+// ListActiveByEvent mocks base method.
+func (m *MockWebhookSubscriptions) ListActiveByEvent(ctx context.Context, eventType string) ([]webhook.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveByEvent", ctx, eventType)
+	ret0, _ := ret[0].([]webhook.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveByEvent indicates an expected call of ListActiveByEvent.
+func (mr *MockWebhookSubscriptionsMockRecorder) ListActiveByEvent(ctx, eventType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveByEvent", reflect.TypeOf((*MockWebhookSubscriptions)(nil).ListActiveByEvent), ctx, eventType)
+}
+
+// RecordFailure mocks base method.
+func (m *MockWebhookSubscriptions) RecordFailure(ctx context.Context, id uuid.UUID, maxFailures int) (webhook.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", ctx, id, maxFailures)
+	ret0, _ := ret[0].(webhook.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockWebhookSubscriptionsMockRecorder) RecordFailure(ctx, id, maxFailures interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockWebhookSubscriptions)(nil).RecordFailure), ctx, id, maxFailures)
+}
+
+// ResetFailures mocks base method.
+func (m *MockWebhookSubscriptions) ResetFailures(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetFailures", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetFailures indicates an expected call of ResetFailures.
+func (mr *MockWebhookSubscriptionsMockRecorder) ResetFailures(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetFailures", reflect.TypeOf((*MockWebhookSubscriptions)(nil).ResetFailures), ctx, id)
+}
+
+// MockWebhookDeliveries is a mock of WebhookDeliveries interface.
+type MockWebhookDeliveries struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookDeliveriesMockRecorder
+}
+
+// MockWebhookDeliveriesMockRecorder is the mock recorder for MockWebhookDeliveries.
+type MockWebhookDeliveriesMockRecorder struct {
+	mock *MockWebhookDeliveries
+}
+
+// NewMockWebhookDeliveries creates a new mock instance.
+func NewMockWebhookDeliveries(ctrl *gomock.Controller) *MockWebhookDeliveries {
+	mock := &MockWebhookDeliveries{ctrl: ctrl}
+	mock.recorder = &MockWebhookDeliveriesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookDeliveries) EXPECT() *MockWebhookDeliveriesMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWebhookDeliveries) Create(ctx context.Context, arg repository.CreateWebhookDeliveryParams) (webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, arg)
+	ret0, _ := ret[0].(webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhookDeliveriesMockRecorder) Create(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhookDeliveries)(nil).Create), ctx, arg)
+}
+
+// CreateAttempt mocks base method.
+func (m *MockWebhookDeliveries) CreateAttempt(ctx context.Context, arg repository.CreateWebhookAttemptParams) (webhook.Attempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAttempt", ctx, arg)
+	ret0, _ := ret[0].(webhook.Attempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAttempt indicates an expected call of CreateAttempt.
+func (mr *MockWebhookDeliveriesMockRecorder) CreateAttempt(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAttempt", reflect.TypeOf((*MockWebhookDeliveries)(nil).CreateAttempt), ctx, arg)
+}
+
+// Get mocks base method.
+func (m *MockWebhookDeliveries) Get(ctx context.Context, id uuid.UUID) (webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockWebhookDeliveriesMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWebhookDeliveries)(nil).Get), ctx, id)
+}
+
+// ListAttempts mocks base method.
+func (m *MockWebhookDeliveries) ListAttempts(ctx context.Context, deliveryID uuid.UUID) ([]webhook.Attempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAttempts", ctx, deliveryID)
+	ret0, _ := ret[0].([]webhook.Attempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAttempts indicates an expected call of ListAttempts.
+func (mr *MockWebhookDeliveriesMockRecorder) ListAttempts(ctx, deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAttempts", reflect.TypeOf((*MockWebhookDeliveries)(nil).ListAttempts), ctx, deliveryID)
+}
+
+// ListBySubscription mocks base method.
+func (m *MockWebhookDeliveries) ListBySubscription(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBySubscription", ctx, subscriptionID, limit)
+	ret0, _ := ret[0].([]webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBySubscription indicates an expected call of ListBySubscription.
+func (mr *MockWebhookDeliveriesMockRecorder) ListBySubscription(ctx, subscriptionID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBySubscription", reflect.TypeOf((*MockWebhookDeliveries)(nil).ListBySubscription), ctx, subscriptionID, limit)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockWebhookDeliveries) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockWebhookDeliveriesMockRecorder) UpdateStatus(ctx, id, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockWebhookDeliveries)(nil).UpdateStatus), ctx, id, status)
+}
+
+// MockJobRuns is a mock of JobRuns interface.
+type MockJobRuns struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobRunsMockRecorder
+}
+
+// MockJobRunsMockRecorder is the mock recorder for MockJobRuns.
+type MockJobRunsMockRecorder struct {
+	mock *MockJobRuns
+}
+
+// NewMockJobRuns creates a new mock instance.
+func NewMockJobRuns(ctrl *gomock.Controller) *MockJobRuns {
+	mock := &MockJobRuns{ctrl: ctrl}
+	mock.recorder = &MockJobRunsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobRuns) EXPECT() *MockJobRunsMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockJobRuns) Create(ctx context.Context, arg repository.CreateJobRunParams) (job.Run, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, arg)
+	ret0, _ := ret[0].(job.Run)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockJobRunsMockRecorder) Create(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockJobRuns)(nil).Create), ctx, arg)
+}
@@ -2,9 +2,12 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	domain_auth "github.com/b0shka/backend/internal/domain/auth"
+	domain_job "github.com/b0shka/backend/internal/domain/job"
 	domain_user "github.com/b0shka/backend/internal/domain/user"
+	domain_webhook "github.com/b0shka/backend/internal/domain/webhook"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -14,31 +17,63 @@ type VerifyEmails interface {
 	Get(ctx context.Context, arg GetVerifyEmailParams) (domain_auth.VerifyEmail, error)
 	DeleteByID(ctx context.Context, id uuid.UUID) error
 	DeleteByEmail(ctx context.Context, email string) error
+	DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error)
 }
 
 type Sessions interface {
 	Create(ctx context.Context, arg CreateSessionParams) (domain_auth.Session, error)
 	Get(ctx context.Context, id uuid.UUID) (domain_auth.Session, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error)
 }
 
 type Users interface {
 	Create(ctx context.Context, arg CreateUserParams) (domain_user.User, error)
 	GetByID(ctx context.Context, id uuid.UUID) (domain_user.User, error)
 	GetByEmail(ctx context.Context, email string) (domain_user.User, error)
+	UpdateLocale(ctx context.Context, id uuid.UUID, locale string) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
+type WebhookSubscriptions interface {
+	Create(ctx context.Context, arg CreateWebhookSubscriptionParams) (domain_webhook.Subscription, error)
+	Get(ctx context.Context, id uuid.UUID) (domain_webhook.Subscription, error)
+	List(ctx context.Context) ([]domain_webhook.Subscription, error)
+	ListActiveByEvent(ctx context.Context, eventType string) ([]domain_webhook.Subscription, error)
+	RecordFailure(ctx context.Context, id uuid.UUID, maxFailures int) (domain_webhook.Subscription, error)
+	ResetFailures(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type WebhookDeliveries interface {
+	Create(ctx context.Context, arg CreateWebhookDeliveryParams) (domain_webhook.Delivery, error)
+	Get(ctx context.Context, id uuid.UUID) (domain_webhook.Delivery, error)
+	ListBySubscription(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]domain_webhook.Delivery, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
+	CreateAttempt(ctx context.Context, arg CreateWebhookAttemptParams) (domain_webhook.Attempt, error)
+	ListAttempts(ctx context.Context, deliveryID uuid.UUID) ([]domain_webhook.Attempt, error)
+}
+
+type JobRuns interface {
+	Create(ctx context.Context, arg CreateJobRunParams) (domain_job.Run, error)
+}
+
 type Repositories struct {
-	VerifyEmails VerifyEmails
-	Sessions     Sessions
-	Users        Users
+	VerifyEmails         VerifyEmails
+	Sessions             Sessions
+	Users                Users
+	WebhookSubscriptions WebhookSubscriptions
+	WebhookDeliveries    WebhookDeliveries
+	JobRuns              JobRuns
 }
 
 func NewRepositories(db *pgxpool.Pool) *Repositories {
 	return &Repositories{
-		VerifyEmails: NewVerifyEmailsRepo(db),
-		Sessions:     NewSessionsRepo(db),
-		Users:        NewUsersRepo(db),
+		VerifyEmails:         NewVerifyEmailsRepo(db),
+		Sessions:             NewSessionsRepo(db),
+		Users:                NewUsersRepo(db),
+		WebhookSubscriptions: NewWebhookSubscriptionsRepo(db),
+		WebhookDeliveries:    NewWebhookDeliveriesRepo(db),
+		JobRuns:              NewJobRunsRepo(db),
 	}
 }
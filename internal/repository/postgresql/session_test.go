@@ -47,6 +47,8 @@ func createRandomSession(t *testing.T, user domain_user.User) domain_auth.Sessio
 		),
 		IsBlocked: false,
 		ExpiresAt: time.Now().Add(time.Hour),
+		RequestID: uuid.NewString(),
+		DeviceID:  uuid.NewString(),
 	}
 
 	session, err := testRepos.Sessions.Create(context.Background(), arg)
@@ -73,6 +75,8 @@ func TestRepository_GetSession(t *testing.T) {
 	require.Equal(t, session1.RefreshToken, session2.RefreshToken)
 	require.Equal(t, session1.UserAgent, session2.UserAgent)
 	require.Equal(t, session1.ClientIP, session2.ClientIP)
+	require.Equal(t, session1.RequestID, session2.RequestID)
+	require.Equal(t, session1.DeviceID, session2.DeviceID)
 	require.Equal(t, session1.IsBlocked, session2.IsBlocked)
 	require.WithinDuration(t, session1.ExpiresAt, session2.ExpiresAt, time.Second)
 }
@@ -30,15 +30,17 @@ type CreateSessionParams struct {
 	ClientIP     string    `json:"client_ip"`
 	IsBlocked    bool      `json:"is_blocked"`
 	ExpiresAt    time.Time `json:"expires_at"`
+	RequestID    string    `json:"request_id"`
+	DeviceID     string    `json:"device_id"`
 }
 
 func (r *SessionsRepo) Create(ctx context.Context, arg CreateSessionParams) (domain_auth.Session, error) {
 	q := `
 		INSERT INTO sessions 
-		    (id, user_id, refresh_token, user_agent, client_ip, is_blocked, expires_at) 
+		    (id, user_id, refresh_token, user_agent, client_ip, is_blocked, expires_at, request_id, device_id) 
 		VALUES 
-			($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, user_id, refresh_token, user_agent, client_ip, is_blocked, expires_at
+			($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, user_id, refresh_token, user_agent, client_ip, is_blocked, expires_at, request_id, device_id
 	`
 
 	var session domain_auth.Session
@@ -53,6 +55,8 @@ func (r *SessionsRepo) Create(ctx context.Context, arg CreateSessionParams) (dom
 			arg.ClientIP,
 			arg.IsBlocked,
 			arg.ExpiresAt,
+			arg.RequestID,
+			arg.DeviceID,
 		).
 		Scan(
 			&session.ID,
@@ -62,6 +66,8 @@ func (r *SessionsRepo) Create(ctx context.Context, arg CreateSessionParams) (dom
 			&session.ClientIP,
 			&session.IsBlocked,
 			&session.ExpiresAt,
+			&session.RequestID,
+			&session.DeviceID,
 		); err != nil {
 		var pgErr *pgconn.PgError
 
@@ -89,7 +95,7 @@ func (r *SessionsRepo) Create(ctx context.Context, arg CreateSessionParams) (dom
 func (r *SessionsRepo) Get(ctx context.Context, id uuid.UUID) (domain_auth.Session, error) {
 	q := `
 		SELECT 
-			id, user_id, refresh_token, user_agent, client_ip, is_blocked, expires_at 
+			id, user_id, refresh_token, user_agent, client_ip, is_blocked, expires_at, request_id, device_id 
 		FROM sessions 
 		WHERE id = $1
 	`
@@ -106,6 +112,8 @@ func (r *SessionsRepo) Get(ctx context.Context, id uuid.UUID) (domain_auth.Sessi
 			&session.ClientIP,
 			&session.IsBlocked,
 			&session.ExpiresAt,
+			&session.RequestID,
+			&session.DeviceID,
 		)
 	if err != nil {
 		return domain_auth.Session{}, err
@@ -123,3 +131,21 @@ func (r *SessionsRepo) Delete(ctx context.Context, id uuid.UUID) error {
 
 	return err
 }
+
+// DeleteExpired removes up to limit sessions that expired before the given
+// time and returns how many rows were deleted.
+func (r *SessionsRepo) DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error) {
+	q := `
+		DELETE FROM sessions
+		WHERE id IN (
+			SELECT id FROM sessions WHERE expires_at < $1 LIMIT $2
+		)
+	`
+
+	tag, err := r.db.Exec(ctx, q, before, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/b0shka/backend/internal/domain"
 	domain_user "github.com/b0shka/backend/internal/domain/user"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -36,7 +37,7 @@ func (r *UsersRepo) Create(ctx context.Context, arg CreateUserParams) (domain_us
 		    (id, email) 
 		VALUES 
 			($1, $2)
-		RETURNING id, email, created_at
+		RETURNING id, email, locale, created_at
 	`
 
 	var user domain_user.User
@@ -45,6 +46,7 @@ func (r *UsersRepo) Create(ctx context.Context, arg CreateUserParams) (domain_us
 		Scan(
 			&user.ID,
 			&user.Email,
+			&user.Locale,
 			&user.CreatedAt,
 		); err != nil {
 		var pgErr *pgconn.PgError
@@ -76,7 +78,7 @@ func (r *UsersRepo) Create(ctx context.Context, arg CreateUserParams) (domain_us
 
 func (r *UsersRepo) GetByID(ctx context.Context, id uuid.UUID) (domain_user.User, error) {
 	q := `
-		SELECT id, email, created_at FROM users WHERE id = $1
+		SELECT id, email, locale, created_at FROM users WHERE id = $1
 	`
 
 	var user domain_user.User
@@ -85,6 +87,7 @@ func (r *UsersRepo) GetByID(ctx context.Context, id uuid.UUID) (domain_user.User
 		Scan(
 			&user.ID,
 			&user.Email,
+			&user.Locale,
 			&user.CreatedAt,
 		); err != nil {
 		return domain_user.User{}, err
@@ -95,7 +98,7 @@ func (r *UsersRepo) GetByID(ctx context.Context, id uuid.UUID) (domain_user.User
 
 func (r *UsersRepo) GetByEmail(ctx context.Context, email string) (domain_user.User, error) {
 	q := `
-		SELECT id, email, created_at FROM users WHERE email = $1
+		SELECT id, email, locale, created_at FROM users WHERE email = $1
 	`
 
 	var user domain_user.User
@@ -104,6 +107,7 @@ func (r *UsersRepo) GetByEmail(ctx context.Context, email string) (domain_user.U
 		Scan(
 			&user.ID,
 			&user.Email,
+			&user.Locale,
 			&user.CreatedAt,
 		); err != nil {
 		return domain_user.User{}, err
@@ -112,6 +116,23 @@ func (r *UsersRepo) GetByEmail(ctx context.Context, email string) (domain_user.U
 	return user, nil
 }
 
+func (r *UsersRepo) UpdateLocale(ctx context.Context, id uuid.UUID, locale string) error {
+	q := `
+		UPDATE users SET locale = $2 WHERE id = $1
+	`
+
+	tag, err := r.db.Exec(ctx, q, id, locale)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
 func (r *UsersRepo) Delete(ctx context.Context, id uuid.UUID) error {
 	q := `
 		DELETE FROM users WHERE id = $1
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/b0shka/backend/internal/domain"
 	domain_user "github.com/b0shka/backend/internal/domain/user"
 	"github.com/b0shka/backend/pkg/utils"
 	"github.com/google/uuid"
@@ -62,6 +63,19 @@ func TestRepository_GetUserByEmail(t *testing.T) {
 	require.WithinDuration(t, user1.CreatedAt, user2.CreatedAt, time.Second)
 }
 
+func TestRepository_UpdateUserLocale(t *testing.T) {
+	user1 := createRandomUser(t)
+	err := testRepos.Users.UpdateLocale(context.Background(), user1.ID, "ru")
+	require.NoError(t, err)
+
+	user2, err := testRepos.Users.GetByID(context.Background(), user1.ID)
+	require.NoError(t, err)
+	require.Equal(t, "ru", user2.Locale)
+
+	err = testRepos.Users.UpdateLocale(context.Background(), uuid.New(), "ru")
+	require.ErrorIs(t, err, domain.ErrUserNotFound)
+}
+
 func TestRepository_DeleteUser(t *testing.T) {
 	user := createRandomUser(t)
 	err := testRepos.Users.Delete(context.Background(), user.ID)
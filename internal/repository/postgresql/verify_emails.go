@@ -127,3 +127,21 @@ func (r *VerifyEmailsRepo) DeleteByEmail(ctx context.Context, email string) erro
 
 	return err
 }
+
+// DeleteExpired removes up to limit codes that expired before the given time
+// and returns how many rows were deleted.
+func (r *VerifyEmailsRepo) DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error) {
+	q := `
+		DELETE FROM verify_emails
+		WHERE id IN (
+			SELECT id FROM verify_emails WHERE expires_at < $1 LIMIT $2
+		)
+	`
+
+	tag, err := r.db.Exec(ctx, q, before, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
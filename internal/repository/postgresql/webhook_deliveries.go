@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	domain_webhook "github.com/b0shka/backend/internal/domain/webhook"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WebhookDeliveriesRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewWebhookDeliveriesRepo(db *pgxpool.Pool) *WebhookDeliveriesRepo {
+	return &WebhookDeliveriesRepo{
+		db: db,
+	}
+}
+
+type CreateWebhookDeliveryParams struct {
+	ID             uuid.UUID       `json:"id"`
+	SubscriptionID uuid.UUID       `json:"subscription_id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	RequestID      string          `json:"request_id"`
+}
+
+func (r *WebhookDeliveriesRepo) Create(
+	ctx context.Context,
+	arg CreateWebhookDeliveryParams,
+) (domain_webhook.Delivery, error) {
+	q := `
+		INSERT INTO webhook_deliveries
+		    (id, subscription_id, event_id, event_type, payload, request_id)
+		VALUES
+			($1, $2, $3, $4, $5, $6)
+		RETURNING id, subscription_id, event_id, event_type, payload, status, attempts, created_at, updated_at, request_id
+	`
+
+	return scanWebhookDelivery(r.db.QueryRow(
+		ctx,
+		q,
+		arg.ID,
+		arg.SubscriptionID,
+		arg.EventID,
+		arg.EventType,
+		arg.Payload,
+		arg.RequestID,
+	))
+}
+
+func (r *WebhookDeliveriesRepo) Get(ctx context.Context, id uuid.UUID) (domain_webhook.Delivery, error) {
+	q := `
+		SELECT id, subscription_id, event_id, event_type, payload, status, attempts, created_at, updated_at, request_id
+		FROM webhook_deliveries
+		WHERE id = $1
+	`
+
+	return scanWebhookDelivery(r.db.QueryRow(ctx, q, id))
+}
+
+func (r *WebhookDeliveriesRepo) ListBySubscription(
+	ctx context.Context,
+	subscriptionID uuid.UUID,
+	limit int,
+) ([]domain_webhook.Delivery, error) {
+	q := `
+		SELECT id, subscription_id, event_id, event_type, payload, status, attempts, created_at, updated_at, request_id
+		FROM webhook_deliveries
+		WHERE subscription_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, q, subscriptionID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain_webhook.Delivery, error) {
+		return scanWebhookDelivery(row)
+	})
+}
+
+func (r *WebhookDeliveriesRepo) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
+	q := `
+		UPDATE webhook_deliveries SET status = $2, updated_at = now() WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, q, id, status)
+
+	return err
+}
+
+type CreateWebhookAttemptParams struct {
+	ID           uuid.UUID     `json:"id"`
+	DeliveryID   uuid.UUID     `json:"delivery_id"`
+	ResponseCode int           `json:"response_code"`
+	Error        string        `json:"error"`
+	Duration     time.Duration `json:"duration"`
+}
+
+// CreateAttempt logs the attempt and increments the attempts counter of the
+// delivery in a single transaction.
+func (r *WebhookDeliveriesRepo) CreateAttempt(
+	ctx context.Context,
+	arg CreateWebhookAttemptParams,
+) (domain_webhook.Attempt, error) {
+	var attempt domain_webhook.Attempt
+
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		q := `
+			INSERT INTO webhook_delivery_attempts
+			    (id, delivery_id, response_code, error, duration_ms)
+			VALUES
+				($1, $2, $3, $4, $5)
+			RETURNING id, delivery_id, response_code, error, duration_ms, created_at
+		`
+
+		var err error
+
+		attempt, err = scanWebhookAttempt(tx.QueryRow(
+			ctx,
+			q,
+			arg.ID,
+			arg.DeliveryID,
+			arg.ResponseCode,
+			arg.Error,
+			arg.Duration.Milliseconds(),
+		))
+		if err != nil {
+			return err
+		}
+
+		q = `
+			UPDATE webhook_deliveries SET attempts = attempts + 1, updated_at = now() WHERE id = $1
+		`
+
+		_, err = tx.Exec(ctx, q, arg.DeliveryID)
+
+		return err
+	})
+	if err != nil {
+		return domain_webhook.Attempt{}, err
+	}
+
+	return attempt, nil
+}
+
+func (r *WebhookDeliveriesRepo) ListAttempts(ctx context.Context, deliveryID uuid.UUID) ([]domain_webhook.Attempt, error) {
+	q := `
+		SELECT id, delivery_id, response_code, error, duration_ms, created_at
+		FROM webhook_delivery_attempts
+		WHERE delivery_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Query(ctx, q, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain_webhook.Attempt, error) {
+		return scanWebhookAttempt(row)
+	})
+}
+
+func scanWebhookDelivery(row pgx.Row) (domain_webhook.Delivery, error) {
+	var delivery domain_webhook.Delivery
+	if err := row.Scan(
+		&delivery.ID,
+		&delivery.SubscriptionID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+		&delivery.RequestID,
+	); err != nil {
+		return domain_webhook.Delivery{}, err
+	}
+
+	return delivery, nil
+}
+
+func scanWebhookAttempt(row pgx.Row) (domain_webhook.Attempt, error) {
+	var (
+		attempt    domain_webhook.Attempt
+		durationMs int64
+	)
+
+	if err := row.Scan(
+		&attempt.ID,
+		&attempt.DeliveryID,
+		&attempt.ResponseCode,
+		&attempt.Error,
+		&durationMs,
+		&attempt.CreatedAt,
+	); err != nil {
+		return domain_webhook.Attempt{}, err
+	}
+
+	attempt.Duration = time.Duration(durationMs) * time.Millisecond
+
+	return attempt, nil
+}
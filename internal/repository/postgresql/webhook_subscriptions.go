@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+
+	domain_webhook "github.com/b0shka/backend/internal/domain/webhook"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WebhookSubscriptionsRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewWebhookSubscriptionsRepo(db *pgxpool.Pool) *WebhookSubscriptionsRepo {
+	return &WebhookSubscriptionsRepo{
+		db: db,
+	}
+}
+
+type CreateWebhookSubscriptionParams struct {
+	ID         uuid.UUID `json:"id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret"`
+	EventTypes []string  `json:"event_types"`
+}
+
+func (r *WebhookSubscriptionsRepo) Create(
+	ctx context.Context,
+	arg CreateWebhookSubscriptionParams,
+) (domain_webhook.Subscription, error) {
+	q := `
+		INSERT INTO webhook_subscriptions
+		    (id, url, secret, event_types)
+		VALUES
+			($1, $2, $3, $4)
+		RETURNING id, url, secret, event_types, is_active, failure_count, created_at
+	`
+
+	return scanWebhookSubscription(r.db.QueryRow(ctx, q, arg.ID, arg.URL, arg.Secret, arg.EventTypes))
+}
+
+func (r *WebhookSubscriptionsRepo) Get(ctx context.Context, id uuid.UUID) (domain_webhook.Subscription, error) {
+	q := `
+		SELECT id, url, secret, event_types, is_active, failure_count, created_at
+		FROM webhook_subscriptions
+		WHERE id = $1
+	`
+
+	return scanWebhookSubscription(r.db.QueryRow(ctx, q, id))
+}
+
+func (r *WebhookSubscriptionsRepo) List(ctx context.Context) ([]domain_webhook.Subscription, error) {
+	q := `
+		SELECT id, url, secret, event_types, is_active, failure_count, created_at
+		FROM webhook_subscriptions
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain_webhook.Subscription, error) {
+		return scanWebhookSubscription(row)
+	})
+}
+
+func (r *WebhookSubscriptionsRepo) ListActiveByEvent(
+	ctx context.Context,
+	eventType string,
+) ([]domain_webhook.Subscription, error) {
+	q := `
+		SELECT id, url, secret, event_types, is_active, failure_count, created_at
+		FROM webhook_subscriptions
+		WHERE is_active AND $1 = ANY(event_types)
+	`
+
+	rows, err := r.db.Query(ctx, q, eventType)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain_webhook.Subscription, error) {
+		return scanWebhookSubscription(row)
+	})
+}
+
+// RecordFailure increments the consecutive failures counter and disables the
+// subscription once the counter reaches maxFailures.
+func (r *WebhookSubscriptionsRepo) RecordFailure(
+	ctx context.Context,
+	id uuid.UUID,
+	maxFailures int,
+) (domain_webhook.Subscription, error) {
+	q := `
+		UPDATE webhook_subscriptions SET
+			failure_count = failure_count + 1,
+			is_active = is_active AND failure_count + 1 < $2
+		WHERE id = $1
+		RETURNING id, url, secret, event_types, is_active, failure_count, created_at
+	`
+
+	return scanWebhookSubscription(r.db.QueryRow(ctx, q, id, maxFailures))
+}
+
+func (r *WebhookSubscriptionsRepo) ResetFailures(ctx context.Context, id uuid.UUID) error {
+	q := `
+		UPDATE webhook_subscriptions SET failure_count = 0 WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, q, id)
+
+	return err
+}
+
+func (r *WebhookSubscriptionsRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	q := `
+		DELETE FROM webhook_subscriptions WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, q, id)
+
+	return err
+}
+
+func scanWebhookSubscription(row pgx.Row) (domain_webhook.Subscription, error) {
+	var subscription domain_webhook.Subscription
+	if err := row.Scan(
+		&subscription.ID,
+		&subscription.URL,
+		&subscription.Secret,
+		&subscription.EventTypes,
+		&subscription.IsActive,
+		&subscription.FailureCount,
+		&subscription.CreatedAt,
+	); err != nil {
+		return domain_webhook.Subscription{}, err
+	}
+
+	return subscription, nil
+}
@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/redis/repository.go
+
+// Package mock_redis is a generated GoMock package.
+package mock_redis
+
+import (
+	context "context"
+	reflect "reflect"
+
+	redis "github.com/b0shka/backend/internal/repository/redis"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSendCodeThrottle is a mock of SendCodeThrottle interface.
+type MockSendCodeThrottle struct {
+	ctrl     *gomock.Controller
+	recorder *MockSendCodeThrottleMockRecorder
+}
+
+// MockSendCodeThrottleMockRecorder is the mock recorder for MockSendCodeThrottle.
+type MockSendCodeThrottleMockRecorder struct {
+	mock *MockSendCodeThrottle
+}
+
+// NewMockSendCodeThrottle creates a new mock instance.
+func NewMockSendCodeThrottle(ctrl *gomock.Controller) *MockSendCodeThrottle {
+	mock := &MockSendCodeThrottle{ctrl: ctrl}
+	mock.recorder = &MockSendCodeThrottleMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSendCodeThrottle) EXPECT() *MockSendCodeThrottleMockRecorder {
+	return m.recorder
+}
+
+// Acquire mocks base method.
+func (m *MockSendCodeThrottle) Acquire(ctx context.Context, arg redis.AcquireSendCodeParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Acquire", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Acquire indicates an expected call of Acquire.
+func (mr *MockSendCodeThrottleMockRecorder) Acquire(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Acquire", reflect.TypeOf((*MockSendCodeThrottle)(nil).Acquire), ctx, arg)
+}
+
+// Release mocks base method.
+func (m *MockSendCodeThrottle) Release(ctx context.Context, arg redis.AcquireSendCodeParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockSendCodeThrottleMockRecorder) Release(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockSendCodeThrottle)(nil).Release), ctx, arg)
+}
@@ -0,0 +1,22 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type SendCodeThrottle interface {
+	Acquire(ctx context.Context, arg AcquireSendCodeParams) error
+	Release(ctx context.Context, arg AcquireSendCodeParams) error
+}
+
+type Repositories struct {
+	SendCodeThrottle SendCodeThrottle
+}
+
+func NewRepositories(client *redis.Client) *Repositories {
+	return &Repositories{
+		SendCodeThrottle: NewSendCodeThrottleRepo(client),
+	}
+}
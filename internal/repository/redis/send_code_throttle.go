@@ -0,0 +1,163 @@
+package redis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/b0shka/backend/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	sendCodeAllowed = iota
+	sendCodeCooldown
+	sendCodeDailyLimit
+)
+
+// acquireSendCodeScript checks the cooldown and the daily caps and records
+// the send in one round trip, so that concurrent requests cannot slip
+// through between the check and the write.
+//
+// KEYS: cooldown, daily counter of the email, daily counter of the ip.
+// ARGV: cooldown ms, email limit, ip limit, ms until the end of the day.
+const acquireSendCodeScript = `
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl > 0 then
+	return {1, ttl}
+end
+
+local emailLimit = tonumber(ARGV[2])
+if emailLimit > 0 and tonumber(redis.call('GET', KEYS[2]) or '0') >= emailLimit then
+	return {2, tonumber(ARGV[4])}
+end
+
+local ipLimit = tonumber(ARGV[3])
+if ipLimit > 0 and tonumber(redis.call('GET', KEYS[3]) or '0') >= ipLimit then
+	return {2, tonumber(ARGV[4])}
+end
+
+if tonumber(ARGV[1]) > 0 then
+	redis.call('SET', KEYS[1], 1, 'PX', ARGV[1])
+end
+
+redis.call('INCR', KEYS[2])
+redis.call('PEXPIRE', KEYS[2], ARGV[4])
+
+if ipLimit > 0 then
+	redis.call('INCR', KEYS[3])
+	redis.call('PEXPIRE', KEYS[3], ARGV[4])
+end
+
+return {0, 0}
+`
+
+// releaseSendCodeScript gives back what acquireSendCodeScript recorded, the
+// counters never go below zero.
+//
+// KEYS: cooldown, daily counter of the email, daily counter of the ip.
+// ARGV: ip limit.
+const releaseSendCodeScript = `
+redis.call('DEL', KEYS[1])
+
+if tonumber(redis.call('GET', KEYS[2]) or '0') > 0 then
+	redis.call('DECR', KEYS[2])
+end
+
+if tonumber(ARGV[1]) > 0 and tonumber(redis.call('GET', KEYS[3]) or '0') > 0 then
+	redis.call('DECR', KEYS[3])
+end
+
+return 0
+`
+
+type SendCodeThrottleRepo struct {
+	client        *redis.Client
+	script        *redis.Script
+	releaseScript *redis.Script
+	now           func() time.Time
+}
+
+func NewSendCodeThrottleRepo(client *redis.Client) *SendCodeThrottleRepo {
+	return &SendCodeThrottleRepo{
+		client:        client,
+		script:        redis.NewScript(acquireSendCodeScript),
+		releaseScript: redis.NewScript(releaseSendCodeScript),
+		now:           time.Now,
+	}
+}
+
+type AcquireSendCodeParams struct {
+	Email              string
+	ClientIP           string
+	Cooldown           time.Duration
+	DailyLimitPerEmail int
+	DailyLimitPerIP    int
+}
+
+// Acquire records a code sent to the email, or returns a
+// *domain.ThrottledError when the email is in cooldown or a daily cap is hit.
+// A zero limit disables the corresponding check.
+func (r *SendCodeThrottleRepo) Acquire(ctx context.Context, arg AcquireSendCodeParams) error {
+	now := r.now().UTC()
+	untilEndOfDay := now.Truncate(24 * time.Hour).Add(24 * time.Hour).Sub(now)
+
+	res, err := r.script.Run(
+		ctx,
+		r.client,
+		sendCodeKeys(arg, now),
+		arg.Cooldown.Milliseconds(),
+		arg.DailyLimitPerEmail,
+		ipLimit(arg),
+		untilEndOfDay.Milliseconds(),
+	).Int64Slice()
+	if err != nil {
+		return err
+	}
+
+	retryAfter := time.Duration(res[1]) * time.Millisecond
+
+	switch res[0] {
+	case sendCodeCooldown:
+		return domain.NewThrottledError(domain.ErrSendCodeCooldown, retryAfter)
+	case sendCodeDailyLimit:
+		return domain.NewThrottledError(domain.ErrSendCodeDailyLimit, retryAfter)
+	default:
+		return nil
+	}
+}
+
+// Release gives back a send recorded by Acquire with the same params, for the
+// code that was never sent.
+func (r *SendCodeThrottleRepo) Release(ctx context.Context, arg AcquireSendCodeParams) error {
+	return r.releaseScript.Run(ctx, r.client, sendCodeKeys(arg, r.now().UTC()), ipLimit(arg)).Err()
+}
+
+func sendCodeKeys(arg AcquireSendCodeParams, now time.Time) []string {
+	day := now.Format("20060102")
+
+	return []string{
+		fmt.Sprintf("send_code:cooldown:%s", hashKey(arg.Email)),
+		fmt.Sprintf("send_code:daily:email:%s:%s", hashKey(arg.Email), day),
+		fmt.Sprintf("send_code:daily:ip:%s:%s", arg.ClientIP, day),
+	}
+}
+
+// ipLimit disables the daily cap of the ip when the ip is not known.
+func ipLimit(arg AcquireSendCodeParams) int {
+	if arg.ClientIP == "" {
+		return 0
+	}
+
+	return arg.DailyLimitPerIP
+}
+
+// hashKey keeps email addresses out of the redis keyspace.
+func hashKey(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(email)))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,126 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/b0shka/backend/internal/domain"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSendCodeThrottle(t *testing.T) (*SendCodeThrottleRepo, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+
+	t.Cleanup(func() {
+		client.Close()
+	})
+
+	repo := NewSendCodeThrottleRepo(client)
+	repo.now = func() time.Time {
+		return time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	}
+
+	return repo, server
+}
+
+func requireThrottled(t *testing.T, err error, target error, retryAfter time.Duration) {
+	var throttledErr *domain.ThrottledError
+
+	require.True(t, errors.As(err, &throttledErr))
+	require.True(t, errors.Is(err, target))
+	require.Equal(t, retryAfter, throttledErr.RetryAfter)
+}
+
+func TestSendCodeThrottle_Cooldown(t *testing.T) {
+	repo, server := newTestSendCodeThrottle(t)
+
+	ctx := context.Background()
+	arg := AcquireSendCodeParams{
+		Email:    "john@example.com",
+		ClientIP: "127.0.0.1",
+		Cooldown: time.Minute,
+	}
+
+	require.NoError(t, repo.Acquire(ctx, arg))
+
+	err := repo.Acquire(ctx, arg)
+	requireThrottled(t, err, domain.ErrSendCodeCooldown, time.Minute)
+
+	server.FastForward(time.Minute)
+	require.NoError(t, repo.Acquire(ctx, arg))
+}
+
+func TestSendCodeThrottle_DailyLimitPerEmail(t *testing.T) {
+	repo, _ := newTestSendCodeThrottle(t)
+
+	ctx := context.Background()
+	arg := AcquireSendCodeParams{
+		Email:              "john@example.com",
+		ClientIP:           "127.0.0.1",
+		DailyLimitPerEmail: 2,
+	}
+
+	require.NoError(t, repo.Acquire(ctx, arg))
+	require.NoError(t, repo.Acquire(ctx, arg))
+
+	err := repo.Acquire(ctx, arg)
+	requireThrottled(t, err, domain.ErrSendCodeDailyLimit, 12*time.Hour)
+
+	arg.Email = "jane@example.com"
+	require.NoError(t, repo.Acquire(ctx, arg))
+}
+
+func TestSendCodeThrottle_DailyLimitPerIP(t *testing.T) {
+	repo, _ := newTestSendCodeThrottle(t)
+
+	ctx := context.Background()
+	arg := AcquireSendCodeParams{
+		Email:           "john@example.com",
+		ClientIP:        "127.0.0.1",
+		DailyLimitPerIP: 1,
+	}
+
+	require.NoError(t, repo.Acquire(ctx, arg))
+
+	arg.Email = "jane@example.com"
+	err := repo.Acquire(ctx, arg)
+	requireThrottled(t, err, domain.ErrSendCodeDailyLimit, 12*time.Hour)
+
+	arg.ClientIP = "127.0.0.2"
+	require.NoError(t, repo.Acquire(ctx, arg))
+}
+
+func TestSendCodeThrottle_Release(t *testing.T) {
+	repo, server := newTestSendCodeThrottle(t)
+
+	ctx := context.Background()
+	arg := AcquireSendCodeParams{
+		Email:              "john@example.com",
+		ClientIP:           "127.0.0.1",
+		Cooldown:           time.Minute,
+		DailyLimitPerEmail: 1,
+		DailyLimitPerIP:    1,
+	}
+
+	require.NoError(t, repo.Acquire(ctx, arg))
+	require.NoError(t, repo.Release(ctx, arg))
+	require.NoError(t, repo.Acquire(ctx, arg))
+
+	err := repo.Acquire(ctx, arg)
+	requireThrottled(t, err, domain.ErrSendCodeCooldown, time.Minute)
+
+	// the counters do not go below zero.
+	require.NoError(t, repo.Release(ctx, arg))
+	require.NoError(t, repo.Release(ctx, arg))
+	require.NoError(t, repo.Acquire(ctx, arg))
+
+	server.FastForward(time.Minute)
+
+	err = repo.Acquire(ctx, arg)
+	requireThrottled(t, err, domain.ErrSendCodeDailyLimit, 12*time.Hour)
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"net"
+
+	"github.com/b0shka/backend/internal/config"
+	"google.golang.org/grpc"
+)
+
+type GRPCServer struct {
+	addr   string
+	server *grpc.Server
+}
+
+func NewGRPCServer(cfg *config.Config, server *grpc.Server) *GRPCServer {
+	return &GRPCServer{
+		addr:   ":" + cfg.GRPC.Port,
+		server: server,
+	}
+}
+
+func (s *GRPCServer) Run() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	return s.server.Serve(listener)
+}
+
+// Stop waits for the calls in flight until the context is done, and then
+// cancels them.
+func (s *GRPCServer) Stop(ctx context.Context) error {
+	stopped := make(chan struct{})
+
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+
+		return ctx.Err()
+	}
+}
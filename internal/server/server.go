@@ -2,23 +2,38 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
 
 	"github.com/b0shka/backend/internal/config"
+	"github.com/b0shka/backend/pkg/logger"
+	"github.com/pires/go-proxyproto"
 )
 
 type Server struct {
-	httpServer *http.Server
+	httpServer     *http.Server
+	redirectServer *http.Server
+	proxyPolicy    proxyproto.PolicyFunc
 }
 
-func NewServer(cfg *config.Config, handler http.Handler) *Server {
+// NewServer serves the handler on the http port. With the PROXY protocol on,
+// the address of the client is taken from the PROXY header of the trusted
+// proxies, the connections of the other peers are served as they are. With
+// TLS on the port serves https and the redirect port, when set, sends the
+// plain http requests there.
+func NewServer(cfg *config.Config, handler http.Handler) (*Server, error) {
 	// Create a new instance of http.Server and assign it to httpServer field of Server struct.
 	httpServer := &http.Server{
-		Addr:           ":" + cfg.HTTP.Port,
-		Handler:        handler,
-		ReadTimeout:    cfg.HTTP.ReadTimeout,
-		WriteTimeout:   cfg.HTTP.WriteTimeout,
-		MaxHeaderBytes: cfg.HTTP.MaxHeaderMegabytes << 20,
+		Addr:              ":" + cfg.HTTP.Port,
+		Handler:           handler,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+		MaxHeaderBytes:    cfg.HTTP.MaxHeaderMegabytes << 20,
 	}
 
 	// Create a new instance of Server struct and assign the httpServer to its httpServer field.
@@ -26,13 +41,109 @@ func NewServer(cfg *config.Config, handler http.Handler) *Server {
 		httpServer: httpServer,
 	}
 
-	return server
+	if cfg.HTTP.ProxyProtocol {
+		policy, err := proxyproto.LaxWhiteListPolicy(cfg.HTTP.TrustedProxies)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+		}
+
+		server.proxyPolicy = policy
+	}
+
+	if !cfg.HTTP.TLS.Enabled {
+		return server, nil
+	}
+
+	tlsConfig, manager, err := newTLSConfig(cfg.HTTP.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	httpServer.TLSConfig = tlsConfig
+
+	if cfg.HTTP.TLS.RedirectPort != "" {
+		var redirect http.Handler = redirectHandler(cfg.HTTP.Port)
+		if manager != nil {
+			// the ACME server checks the http-01 challenges on the plain port.
+			redirect = manager.HTTPHandler(redirect)
+		}
+
+		server.redirectServer = &http.Server{
+			Addr:              ":" + cfg.HTTP.TLS.RedirectPort,
+			Handler:           redirect,
+			ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+			WriteTimeout:      cfg.HTTP.WriteTimeout,
+			IdleTimeout:       cfg.HTTP.IdleTimeout,
+		}
+	}
+
+	return server, nil
+}
+
+// NewAdminServer serves the operational endpoints on their own address, so
+// that they are not exposed together with the public api. There is no write
+// timeout, the profiles and the traces of pprof take longer.
+func NewAdminServer(cfg *config.Config, handler http.Handler) *Server {
+	return &Server{
+		httpServer: &http.Server{
+			Addr:              cfg.AdminServer.Address,
+			Handler:           handler,
+			ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+			IdleTimeout:       cfg.HTTP.IdleTimeout,
+		},
+	}
 }
 
 func (s *Server) Run() error {
-	return s.httpServer.ListenAndServe()
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return err
+	}
+
+	if s.redirectServer != nil {
+		go func() {
+			if err := s.redirectServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("error occurred while running http redirect server", logger.Err(err))
+			}
+		}()
+	}
+
+	return s.Serve(listener)
+}
+
+// Serve serves the connections of the listener, the PROXY protocol is read
+// before the TLS handshake.
+func (s *Server) Serve(listener net.Listener) error {
+	if s.proxyPolicy != nil {
+		listener = &proxyproto.Listener{
+			Listener:          listener,
+			Policy:            s.proxyPolicy,
+			ReadHeaderTimeout: s.httpServer.ReadHeaderTimeout,
+		}
+	}
+
+	if s.httpServer.TLSConfig != nil {
+		return s.httpServer.ServeTLS(listener, "", "")
+	}
+
+	return s.httpServer.Serve(listener)
 }
 
+// Stop shuts the redirect server down along with the server, a redirect
+// server that fails to stop does not keep the requests of the server from
+// draining.
 func (s *Server) Stop(ctx context.Context) error {
-	return s.httpServer.Shutdown(ctx)
+	if s.redirectServer == nil {
+		return s.httpServer.Shutdown(ctx)
+	}
+
+	redirectErr := make(chan error, 1)
+
+	go func() {
+		redirectErr <- s.redirectServer.Shutdown(ctx)
+	}()
+
+	err := s.httpServer.Shutdown(ctx)
+
+	return errors.Join(err, <-redirectErr)
 }
@@ -0,0 +1,386 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/b0shka/backend/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCert issues a certificate for localhost, self-signed when the parent
+// is nil.
+func newTestCert(t *testing.T, name string, parent *testCert) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func (c *testCert) writeFiles(t *testing.T, dir string) (string, string) {
+	t.Helper()
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	require.NoError(t, os.WriteFile(certFile, c.certPEM, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, c.keyPEM, 0o600))
+
+	return certFile, keyFile
+}
+
+func (c *testCert) tlsCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+
+	cert, err := tls.X509KeyPair(c.certPEM, c.keyPEM)
+	require.NoError(t, err)
+
+	return cert
+}
+
+// serveTest serves the server on a free port of localhost and returns the
+// https address.
+func serveTest(t *testing.T, cfg *config.Config) string {
+	t.Helper()
+
+	server, err := NewServer(cfg, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Proto)
+	}))
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	t.Cleanup(func() {
+		_ = server.httpServer.Close()
+	})
+
+	return "https://" + listener.Addr().String()
+}
+
+func newTestClient(roots *x509.CertPool, certs ...tls.Certificate) *http.Client {
+	return &http.Client{
+		Timeout: time.Second * 5,
+		Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{RootCAs: roots, Certificates: certs, MinVersion: tls.VersionTLS12},
+			ForceAttemptHTTP2: true,
+		},
+	}
+}
+
+func TestServer_TLS(t *testing.T) {
+	ca := newTestCert(t, "ca", nil)
+	serverCert := newTestCert(t, "server", ca)
+	certFile, keyFile := serverCert.writeFiles(t, t.TempDir())
+
+	cfg := &config.Config{}
+	cfg.HTTP.TLS = config.TLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile}
+
+	addr := serveTest(t, cfg)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	res, err := newTestClient(roots).Get(addr)
+	require.NoError(t, err)
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "HTTP/2.0", string(body))
+
+	_, err = newTestClient(x509.NewCertPool()).Get(addr)
+	require.Error(t, err)
+}
+
+func TestServer_MutualTLS(t *testing.T) {
+	ca := newTestCert(t, "ca", nil)
+	serverCert := newTestCert(t, "server", ca)
+	certFile, keyFile := serverCert.writeFiles(t, t.TempDir())
+
+	clientCA := newTestCert(t, "client ca", nil)
+	clientCAFile := filepath.Join(t.TempDir(), "client_ca.pem")
+	require.NoError(t, os.WriteFile(clientCAFile, clientCA.certPEM, 0o600))
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	tests := []struct {
+		name              string
+		requireClientCert bool
+		clientCert        *testCert
+		ok                bool
+	}{
+		{
+			name:              "client cert",
+			requireClientCert: true,
+			clientCert:        newTestCert(t, "client", clientCA),
+			ok:                true,
+		},
+		{
+			name:              "no client cert",
+			requireClientCert: true,
+		},
+		{
+			name:              "unknown client cert",
+			requireClientCert: true,
+			clientCert:        newTestCert(t, "client", ca),
+		},
+		{
+			name: "optional client cert",
+			ok:   true,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.HTTP.TLS = config.TLSConfig{
+				Enabled:           true,
+				CertFile:          certFile,
+				KeyFile:           keyFile,
+				ClientCAFile:      clientCAFile,
+				RequireClientCert: testCase.requireClientCert,
+			}
+
+			addr := serveTest(t, cfg)
+
+			var certs []tls.Certificate
+			if testCase.clientCert != nil {
+				certs = append(certs, testCase.clientCert.tlsCertificate(t))
+			}
+
+			res, err := newTestClient(roots, certs...).Get(addr)
+			if !testCase.ok {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+		})
+	}
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+
+	first := newTestCert(t, "first", nil)
+	certFile, keyFile := first.writeFiles(t, dir)
+
+	reloader, err := newCertReloader(certFile, keyFile, time.Nanosecond)
+	require.NoError(t, err)
+
+	cert, err := reloader.GetCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, first.certPEM, pemOf(cert))
+
+	second := newTestCert(t, "second", nil)
+	second.writeFiles(t, dir)
+	touch(t, time.Now().Add(time.Minute), certFile, keyFile)
+
+	cert, err = reloader.GetCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, second.certPEM, pemOf(cert))
+
+	require.NoError(t, os.WriteFile(certFile, []byte("broken"), 0o600))
+	touch(t, time.Now().Add(time.Minute*2), certFile)
+
+	cert, err = reloader.GetCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, second.certPEM, pemOf(cert))
+
+	_, err = newCertReloader(certFile, keyFile, time.Minute)
+	require.Error(t, err)
+}
+
+func pemOf(cert *tls.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+}
+
+func touch(t *testing.T, modTime time.Time, files ...string) {
+	t.Helper()
+
+	for _, file := range files {
+		require.NoError(t, os.Chtimes(file, modTime, modTime))
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	_, _, err := newTLSConfig(config.TLSConfig{})
+	require.Error(t, err)
+
+	_, _, err = newTLSConfig(config.TLSConfig{CertFile: "cert.pem", Autocert: config.AutocertConfig{Enabled: true}})
+	require.Error(t, err)
+
+	_, _, err = newTLSConfig(config.TLSConfig{Autocert: config.AutocertConfig{Enabled: true}})
+	require.Error(t, err)
+
+	tlsConfig, manager, err := newTLSConfig(config.TLSConfig{
+		Autocert: config.AutocertConfig{Enabled: true, Hosts: []string{"api.example.com"}, CacheDir: t.TempDir()},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, manager)
+	require.Contains(t, tlsConfig.NextProtos, "h2")
+	require.Contains(t, tlsConfig.NextProtos, "acme-tls/1")
+}
+
+func TestRedirectHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		httpsPort string
+		host      string
+		target    string
+		location  string
+	}{
+		{
+			name:      "default port",
+			httpsPort: "443",
+			host:      "api.example.com",
+			target:    "/api/v1/users?a=b",
+			location:  "https://api.example.com/api/v1/users?a=b",
+		},
+		{
+			name:      "custom port",
+			httpsPort: "8443",
+			host:      "api.example.com:8080",
+			target:    "/ping",
+			location:  "https://api.example.com:8443/ping",
+		},
+		{
+			name:      "ipv6",
+			httpsPort: "443",
+			host:      "[::1]:8080",
+			target:    "/",
+			location:  "https://[::1]/",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, testCase.target, nil)
+			req.Host = testCase.host
+
+			redirectHandler(testCase.httpsPort).ServeHTTP(recorder, req)
+
+			require.Equal(t, http.StatusPermanentRedirect, recorder.Code)
+			require.Equal(t, testCase.location, recorder.Header().Get("Location"))
+		})
+	}
+}
+
+func TestServer_StopRedirectTimeout(t *testing.T) {
+	redirectListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &Server{
+		httpServer:     &http.Server{ReadHeaderTimeout: time.Minute},
+		redirectServer: &http.Server{Handler: redirectHandler("443"), ReadHeaderTimeout: time.Minute},
+	}
+
+	served := make(chan error, 1)
+
+	go func() {
+		served <- server.Serve(listener)
+	}()
+
+	go func() {
+		_ = server.redirectServer.Serve(redirectListener)
+	}()
+
+	// a connection that sends nothing keeps the redirect server from
+	// shutting down.
+	conn, err := net.Dial("tcp", redirectListener.Addr().String())
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	time.Sleep(time.Millisecond * 50)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+
+	require.ErrorIs(t, server.Stop(ctx), context.DeadlineExceeded)
+
+	select {
+	case err := <-served:
+		require.ErrorIs(t, err, http.ErrServerClosed)
+	case <-time.After(time.Second):
+		t.Fatal("server is not stopped")
+	}
+
+	_ = server.redirectServer.Close()
+}
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/b0shka/backend/internal/config"
+	"github.com/b0shka/backend/pkg/logger"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+const defaultCertReloadInterval = time.Minute
+
+// newTLSConfig serves the certificate of the files, or the certificates the
+// ACME server issues for the hosts. HTTP/2 is negotiated with ALPN. The
+// autocert manager is returned to answer its challenges on the plain http
+// port.
+func newTLSConfig(cfg config.TLSConfig) (*tls.Config, *autocert.Manager, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"h2", "http/1.1"},
+	}
+
+	var manager *autocert.Manager
+
+	switch {
+	case cfg.Autocert.Enabled && cfg.CertFile != "":
+		return nil, nil, errors.New("tls certificate files and autocert are exclusive")
+	case cfg.Autocert.Enabled:
+		if len(cfg.Autocert.Hosts) == 0 {
+			return nil, nil, errors.New("autocert needs the hosts to issue the certificates for")
+		}
+
+		manager = &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.Autocert.Hosts...),
+			Cache:      autocert.DirCache(cfg.Autocert.CacheDir),
+			Email:      cfg.Autocert.Email,
+		}
+
+		tlsConfig.GetCertificate = manager.GetCertificate
+		tlsConfig.NextProtos = append(tlsConfig.NextProtos, acme.ALPNProto)
+	default:
+		reloader, err := newCertReloader(cfg.CertFile, cfg.KeyFile, cfg.ReloadInterval)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		tlsConfig.GetCertificate = reloader.GetCertificate
+	}
+
+	if cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read client ca: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, nil, fmt.Errorf("no certificates in client ca: %s", cfg.ClientCAFile)
+		}
+
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+
+		if cfg.RequireClientCert {
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	return tlsConfig, manager, nil
+}
+
+// certReloader serves the certificate of the files and loads it again once
+// the files change, so that a renewed certificate is picked up without a
+// restart. The files are checked on the handshakes, at most once per
+// interval. A certificate that fails to load is logged and the previous one
+// is kept.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+func newCertReloader(certFile, keyFile string, interval time.Duration) (*certReloader, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("tls needs the certificate and the key files or autocert")
+	}
+
+	if interval <= 0 {
+		interval = defaultCertReloadInterval
+	}
+
+	r := &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		interval: interval,
+	}
+
+	modTime, err := r.filesModTime()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.load(modTime); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checkedAt) < r.interval {
+		return r.cert, nil
+	}
+
+	r.checkedAt = time.Now()
+
+	modTime, err := r.filesModTime()
+	if err != nil {
+		slog.Error("failed to check tls certificate", logger.Err(err))
+
+		return r.cert, nil
+	}
+
+	if !modTime.Equal(r.modTime) {
+		if err := r.load(modTime); err != nil {
+			slog.Error("failed to reload tls certificate", logger.Err(err))
+		} else {
+			slog.Info("tls certificate reloaded", "file", r.certFile)
+		}
+	}
+
+	return r.cert, nil
+}
+
+func (r *certReloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load tls certificate: %w", err)
+	}
+
+	r.cert = &cert
+	r.modTime = modTime
+	r.checkedAt = time.Now()
+
+	return nil
+}
+
+// filesModTime is the time of the latest change of the files, the
+// certificate and the key are usually replaced one after the other.
+func (r *certReloader) filesModTime() (time.Time, error) {
+	var latest time.Time
+
+	for _, file := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest, nil
+}
+
+// redirectHandler sends the plain http requests to the same url on the https
+// port.
+func redirectHandler(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+
+		switch {
+		case httpsPort != "" && httpsPort != "443":
+			host = net.JoinHostPort(host, httpsPort)
+		case strings.Contains(host, ":"):
+			host = "[" + host + "]"
+		}
+
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
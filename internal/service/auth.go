@@ -2,18 +2,29 @@ package service
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/b0shka/backend/internal/config"
 	"github.com/b0shka/backend/internal/domain"
 	domain_auth "github.com/b0shka/backend/internal/domain/auth"
+	"github.com/b0shka/backend/internal/event"
+	"github.com/b0shka/backend/internal/metrics"
 	repository "github.com/b0shka/backend/internal/repository/postgresql"
+	sqlc "github.com/b0shka/backend/internal/repository/postgresql/sqlc"
+	cache "github.com/b0shka/backend/internal/repository/redis"
 	"github.com/b0shka/backend/internal/worker"
 	"github.com/b0shka/backend/pkg/auth"
+	"github.com/b0shka/backend/pkg/clientinfo"
 	"github.com/b0shka/backend/pkg/hash"
 	"github.com/b0shka/backend/pkg/identity"
+	"github.com/b0shka/backend/pkg/logger"
 	"github.com/b0shka/backend/pkg/otp"
-	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
 )
@@ -26,49 +37,93 @@ type AuthService struct {
 	repoUsers        repository.Users
 	repoSessions     repository.Sessions
 	repoVerifyEmails repository.VerifyEmails
+	sendCodeThrottle cache.SendCodeThrottle
 	hasher           hash.Hasher
 	tokenManager     auth.Manager
 	otpGenerator     otp.Generator
 	idGenerator      identity.Generator
 	authConfig       config.AuthConfig
 	taskDistributor  worker.TaskDistributor
+	eventPublisher   event.Publisher
 }
 
 func NewAuthService(
 	repoUsers repository.Users,
 	repoSessions repository.Sessions,
 	repoVerifyEmails repository.VerifyEmails,
+	sendCodeThrottle cache.SendCodeThrottle,
 	hasher hash.Hasher,
 	tokenManager auth.Manager,
 	otpGenerator otp.Generator,
 	idGenerator identity.Generator,
 	authConfig config.AuthConfig,
 	taskDistributor worker.TaskDistributor,
+	eventPublisher event.Publisher,
 ) *AuthService {
 	return &AuthService{
 		repoVerifyEmails: repoVerifyEmails,
 		repoSessions:     repoSessions,
 		repoUsers:        repoUsers,
+		sendCodeThrottle: sendCodeThrottle,
 		hasher:           hasher,
 		tokenManager:     tokenManager,
 		otpGenerator:     otpGenerator,
 		idGenerator:      idGenerator,
 		authConfig:       authConfig,
 		taskDistributor:  taskDistributor,
+		eventPublisher:   eventPublisher,
 	}
 }
 
 func (s *AuthService) SendCodeEmail(ctx context.Context, inp domain_auth.SendCodeEmailInput) error {
+	ctx, span := startSpan(ctx, "AuthService.SendCodeEmail")
+	defer span.End()
+
+	err := s.sendCodeEmail(ctx, inp)
+	metrics.ObserveAuth(metrics.AuthOperationSendCode, authOutcome(err))
+	endSpan(span, err)
+
+	return err
+}
+
+// sendCodeEmail gives the send back to the throttle when the code is not
+// sent, so that the client is not throttled for a retry. A send rejected as a
+// duplicate by the task id is kept.
+func (s *AuthService) sendCodeEmail(ctx context.Context, inp domain_auth.SendCodeEmailInput) error {
+	acquired, err := s.acquireSendCode(ctx, inp)
+	if err != nil {
+		return err
+	}
+
+	err = s.sendCode(ctx, inp)
+	if err != nil && acquired && !errors.Is(err, domain.ErrSendCodeCooldown) {
+		if err := s.sendCodeThrottle.Release(ctx, s.sendCodeParams(inp)); err != nil {
+			logger.FromContext(ctx).Error("failed to release send code throttle", logger.Err(err))
+		}
+	}
+
+	return err
+}
+
+func (s *AuthService) sendCode(ctx context.Context, inp domain_auth.SendCodeEmailInput) error {
 	userParams := repository.CreateUserParams{
 		ID:    s.idGenerator.GenerateUUID(),
 		Email: inp.Email,
 	}
 
-	_, err := s.repoUsers.Create(ctx, userParams)
+	user, err := s.repoUsers.Create(ctx, userParams)
 	if err != nil {
 		return err
 	}
 
+	// an empty user is returned when the email is already registered
+	if user.ID != uuid.Nil {
+		publishEvent(ctx, s.eventPublisher, event.New(event.TypeUserCreated, event.UserCreated{
+			UserID: user.ID,
+			Email:  user.Email,
+		}))
+	}
+
 	secretCode := s.otpGenerator.RandomCode(s.authConfig.VerificationCodeLength)
 	taskPayload := &worker.PayloadSendVerifyEmail{
 		Email:      inp.Email,
@@ -80,10 +135,81 @@ func (s *AuthService) SendCodeEmail(ctx context.Context, inp domain_auth.SendCod
 		asynq.Queue(worker.QueueCritical),
 	}
 
-	return s.taskDistributor.DistributeTaskSendVerifyEmail(ctx, taskPayload, opts...)
+	cooldown := s.authConfig.SendCode.Cooldown
+	if cooldown <= 0 {
+		return s.taskDistributor.DistributeTaskSendVerifyEmail(ctx, taskPayload, opts...)
+	}
+
+	// the task id is shared by every request for the email within one
+	// cooldown window, so the redis backend rejects duplicates even when the
+	// cooldown store is unavailable. The rabbitmq backend does not
+	// deduplicate the tasks, only the cooldown store protects the mailbox.
+	now := time.Now()
+	window := now.Truncate(cooldown)
+	opts = append(opts, asynq.TaskID(s.sendCodeTaskID(inp.Email, window)))
+
+	err = s.taskDistributor.DistributeTaskSendVerifyEmail(ctx, taskPayload, opts...)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return domain.NewThrottledError(domain.ErrSendCodeCooldown, window.Add(cooldown).Sub(now))
+	}
+
+	return err
+}
+
+// sendCodeTaskID identifies the email by its HMAC keyed with the secret key.
+// The ids are shown by the task inspector, a plain hash of the email would be
+// reversed with a dictionary.
+func (s *AuthService) sendCodeTaskID(email string, window time.Time) string {
+	mac := hmac.New(sha256.New, []byte(s.authConfig.SecretKey))
+	mac.Write([]byte(strings.ToLower(email)))
+
+	return fmt.Sprintf("%s:%s:%d", worker.TaskSendVerifyEmail, hex.EncodeToString(mac.Sum(nil)), window.Unix())
+}
+
+// acquireSendCode enforces the resend cooldown and the daily caps and
+// reports whether the send was recorded. Failures of the store itself are
+// logged and let the request through, the task id deduplication still
+// protects the mailbox.
+func (s *AuthService) acquireSendCode(ctx context.Context, inp domain_auth.SendCodeEmailInput) (bool, error) {
+	err := s.sendCodeThrottle.Acquire(ctx, s.sendCodeParams(inp))
+	if err != nil {
+		var throttledErr *domain.ThrottledError
+		if errors.As(err, &throttledErr) {
+			return false, err
+		}
+
+		logger.FromContext(ctx).Error("failed to check send code throttle", logger.Err(err))
+
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (s *AuthService) sendCodeParams(inp domain_auth.SendCodeEmailInput) cache.AcquireSendCodeParams {
+	return cache.AcquireSendCodeParams{
+		Email:              inp.Email,
+		ClientIP:           inp.ClientIP,
+		Cooldown:           s.authConfig.SendCode.Cooldown,
+		DailyLimitPerEmail: s.authConfig.SendCode.DailyLimitPerEmail,
+		DailyLimitPerIP:    s.authConfig.SendCode.DailyLimitPerIP,
+	}
+}
+
+// SignIn opens a session for the client of the context, the transports put
+// it there with clientinfo.WithContext.
+func (s *AuthService) SignIn(ctx context.Context, inp domain_auth.SignInInput) (domain_auth.SignInOutput, error) {
+	ctx, span := startSpan(ctx, "AuthService.SignIn")
+	defer span.End()
+
+	res, err := s.signIn(ctx, inp)
+	metrics.ObserveAuth(metrics.AuthOperationSignIn, authOutcome(err))
+	endSpan(span, err)
+
+	return res, err
 }
 
-func (s *AuthService) SignIn(ctx *gin.Context, inp domain_auth.SignInInput) (domain_auth.SignInOutput, error) {
+func (s *AuthService) signIn(ctx context.Context, inp domain_auth.SignInInput) (domain_auth.SignInOutput, error) {
 	secretCodeHash, err := s.hasher.HashCode(inp.SecretCode)
 	if err != nil {
 		return domain_auth.SignInOutput{}, err
@@ -118,10 +244,19 @@ func (s *AuthService) SignIn(ctx *gin.Context, inp domain_auth.SignInInput) (dom
 		return domain_auth.SignInOutput{}, err
 	}
 
+	client := clientinfo.FromContext(ctx)
+
+	publishEvent(ctx, s.eventPublisher, event.New(event.TypeUserSignedIn, event.UserSignedIn{
+		UserID:    user.ID,
+		SessionID: tokens.SessionID,
+		UserAgent: client.UserAgent,
+		ClientIP:  client.IP,
+	}))
+
 	taskPayload := &worker.PayloadSendLoginNotification{
 		Email:     inp.Email,
-		UserAgent: ctx.Request.UserAgent(),
-		ClientIP:  ctx.ClientIP(),
+		UserAgent: client.UserAgent,
+		ClientIP:  client.IP,
 		Time:      time.Now().Format(formatTimeLayout),
 	}
 	opts := []asynq.Option{
@@ -138,7 +273,7 @@ func (s *AuthService) SignIn(ctx *gin.Context, inp domain_auth.SignInInput) (dom
 	return tokens, nil
 }
 
-func (s *AuthService) createSession(ctx *gin.Context, id uuid.UUID) (domain_auth.SignInOutput, error) {
+func (s *AuthService) createSession(ctx context.Context, id uuid.UUID) (domain_auth.SignInOutput, error) {
 	var res domain_auth.SignInOutput
 
 	refreshToken, refreshPayload, err := s.tokenManager.CreateToken(
@@ -162,14 +297,17 @@ func (s *AuthService) createSession(ctx *gin.Context, id uuid.UUID) (domain_auth
 
 	res.AccessToken = accessToken
 
+	client := clientinfo.FromContext(ctx)
 	sessionParams := repository.CreateSessionParams{
 		ID:           refreshPayload.ID,
 		UserID:       id,
 		RefreshToken: res.RefreshToken,
-		UserAgent:    ctx.Request.UserAgent(),
-		ClientIP:     ctx.ClientIP(),
+		UserAgent:    client.UserAgent,
+		ClientIP:     client.IP,
 		IsBlocked:    false,
 		ExpiresAt:    refreshPayload.ExpiresAt,
+		RequestID:    client.RequestID,
+		DeviceID:     client.DeviceID,
 	}
 
 	if _, err := s.repoSessions.Create(ctx, sessionParams); err != nil {
@@ -182,6 +320,20 @@ func (s *AuthService) createSession(ctx *gin.Context, id uuid.UUID) (domain_auth
 func (s *AuthService) RefreshToken(
 	ctx context.Context,
 	inp domain_auth.RefreshTokenInput,
+) (domain_auth.RefreshTokenOutput, error) {
+	ctx, span := startSpan(ctx, "AuthService.RefreshToken")
+	defer span.End()
+
+	res, err := s.refreshToken(ctx, inp)
+	metrics.ObserveAuth(metrics.AuthOperationRefresh, authOutcome(err))
+	endSpan(span, err)
+
+	return res, err
+}
+
+func (s *AuthService) refreshToken(
+	ctx context.Context,
+	inp domain_auth.RefreshTokenInput,
 ) (domain_auth.RefreshTokenOutput, error) {
 	var res domain_auth.RefreshTokenOutput
 
@@ -190,8 +342,14 @@ func (s *AuthService) RefreshToken(
 		return domain_auth.RefreshTokenOutput{}, err
 	}
 
+	ctx = logger.With(ctx, logger.KeySessionID, refreshPayload.ID, logger.KeyUserID, refreshPayload.UserID)
+
 	session, err := s.repoSessions.Get(ctx, refreshPayload.ID)
 	if err != nil {
+		if errors.Is(err, sqlc.ErrRecordNotFound) {
+			return domain_auth.RefreshTokenOutput{}, domain.ErrSessionNotFound
+		}
+
 		return domain_auth.RefreshTokenOutput{}, err
 	}
 
@@ -223,3 +381,36 @@ func (s *AuthService) RefreshToken(
 
 	return res, nil
 }
+
+// authOutcome names the result of an authentication attempt for the metrics,
+// unexpected errors are not told apart to keep the cardinality bounded.
+func authOutcome(err error) string {
+	outcomes := []struct {
+		err     error
+		outcome string
+	}{
+		{domain.ErrSendCodeCooldown, "cooldown"},
+		{domain.ErrSendCodeDailyLimit, "daily_limit"},
+		{domain.ErrSecretCodeInvalid, "invalid_code"},
+		{domain.ErrSecretCodeExpired, "expired_code"},
+		{domain.ErrInvalidToken, "invalid_token"},
+		{domain.ErrExpiredToken, "expired_token"},
+		{domain.ErrSessionBlocked, "session_blocked"},
+		{domain.ErrIncorrectSessionUser, "incorrect_session_user"},
+		{domain.ErrMismatchedSession, "mismatched_session"},
+		{domain.ErrSessionNotFound, "not_found"},
+		{sqlc.ErrRecordNotFound, "not_found"},
+	}
+
+	if err == nil {
+		return metrics.StatusSuccess
+	}
+
+	for _, o := range outcomes {
+		if errors.Is(err, o.err) {
+			return o.outcome
+		}
+	}
+
+	return "error"
+}
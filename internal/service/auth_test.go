@@ -2,8 +2,11 @@ package service_test
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
-	"net/http/httptest"
+	"fmt"
 	"testing"
 	"time"
 
@@ -11,17 +14,23 @@ import (
 	"github.com/b0shka/backend/internal/domain"
 	domain_auth "github.com/b0shka/backend/internal/domain/auth"
 	domain_user "github.com/b0shka/backend/internal/domain/user"
+	mock_event "github.com/b0shka/backend/internal/event/mocks"
+	repository "github.com/b0shka/backend/internal/repository/postgresql"
 	mock_repository "github.com/b0shka/backend/internal/repository/postgresql/mocks"
+	cache "github.com/b0shka/backend/internal/repository/redis"
+	mock_redis "github.com/b0shka/backend/internal/repository/redis/mocks"
 	"github.com/b0shka/backend/internal/service"
+	workerpkg "github.com/b0shka/backend/internal/worker"
 	mock_worker "github.com/b0shka/backend/internal/worker/mocks"
 	"github.com/b0shka/backend/pkg/auth"
+	"github.com/b0shka/backend/pkg/clientinfo"
 	"github.com/b0shka/backend/pkg/hash"
 	"github.com/b0shka/backend/pkg/identity"
 	"github.com/b0shka/backend/pkg/otp"
 	"github.com/b0shka/backend/pkg/utils"
-	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
 	"github.com/stretchr/testify/require"
 )
 
@@ -39,25 +48,184 @@ func mockAuthService(t *testing.T) (
 	workerCtl := gomock.NewController(t)
 	defer workerCtl.Finish()
 
+	eventCtl := gomock.NewController(t)
+	defer eventCtl.Finish()
+
 	repoUsers := mock_repository.NewMockUsers(repoCtl)
 	repoSessions := mock_repository.NewMockSessions(repoCtl)
 	repoVerifyEmails := mock_repository.NewMockVerifyEmails(repoCtl)
+	sendCodeThrottle := mock_redis.NewMockSendCodeThrottle(repoCtl)
 	worker := mock_worker.NewMockTaskDistributor(workerCtl)
+	eventPublisher := mock_event.NewMockPublisher(eventCtl)
 	authService := service.NewAuthService(
 		repoUsers,
 		repoSessions,
 		repoVerifyEmails,
+		sendCodeThrottle,
 		&hash.SHA256Hasher{},
 		&auth.JWTManager{},
 		&otp.TOTPGenerator{},
 		&identity.IDGenerator{},
 		config.AuthConfig{},
 		worker,
+		eventPublisher,
 	)
 
 	return authService, repoUsers, repoSessions, repoVerifyEmails
 }
 
+func mockSendCodeAuthService(t *testing.T, authConfig config.AuthConfig) (
+	*service.AuthService,
+	*mock_repository.MockUsers,
+	*mock_redis.MockSendCodeThrottle,
+	*mock_worker.MockTaskDistributor,
+) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	repoUsers := mock_repository.NewMockUsers(mockCtl)
+	sendCodeThrottle := mock_redis.NewMockSendCodeThrottle(mockCtl)
+	worker := mock_worker.NewMockTaskDistributor(mockCtl)
+	authService := service.NewAuthService(
+		repoUsers,
+		mock_repository.NewMockSessions(mockCtl),
+		mock_repository.NewMockVerifyEmails(mockCtl),
+		sendCodeThrottle,
+		&hash.SHA256Hasher{},
+		&auth.JWTManager{},
+		&otp.TOTPGenerator{},
+		&identity.IDGenerator{},
+		authConfig,
+		worker,
+		mock_event.NewMockPublisher(mockCtl),
+	)
+
+	return authService, repoUsers, sendCodeThrottle, worker
+}
+
+func TestAuthService_SendCodeEmailThrottled(t *testing.T) {
+	authService, userRepo, sendCodeThrottle, worker := mockSendCodeAuthService(t, config.AuthConfig{})
+
+	ctx := context.Background()
+	throttledErr := domain.NewThrottledError(domain.ErrSendCodeCooldown, time.Minute)
+
+	sendCodeThrottle.EXPECT().Acquire(gomock.Any(), gomock.Any()).Return(throttledErr)
+	userRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+	worker.EXPECT().DistributeTaskSendVerifyEmail(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	err := authService.SendCodeEmail(ctx, domain_auth.NewSendCodeEmailInput("email@ya.ru", "127.0.0.1"))
+	require.ErrorIs(t, err, domain.ErrSendCodeCooldown)
+}
+
+func TestAuthService_SendCodeEmailDuplicateTask(t *testing.T) {
+	authConfig := config.AuthConfig{
+		SendCode: config.SendCodeConfig{Cooldown: time.Minute},
+	}
+	authService, userRepo, sendCodeThrottle, worker := mockSendCodeAuthService(t, authConfig)
+
+	ctx := context.Background()
+
+	// the cooldown store is down, the task id still rejects the duplicate
+	sendCodeThrottle.EXPECT().Acquire(gomock.Any(), gomock.Any()).Return(ErrInternalServerError)
+	userRepo.EXPECT().Create(gomock.Any(), gomock.Any())
+	worker.EXPECT().DistributeTaskSendVerifyEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(fmt.Errorf("failed to enqueue task: %w", asynq.ErrTaskIDConflict))
+
+	err := authService.SendCodeEmail(ctx, domain_auth.NewSendCodeEmailInput("email@ya.ru", "127.0.0.1"))
+
+	var throttledErr *domain.ThrottledError
+
+	require.ErrorAs(t, err, &throttledErr)
+	require.ErrorIs(t, err, domain.ErrSendCodeCooldown)
+	require.LessOrEqual(t, throttledErr.RetryAfter, time.Minute)
+}
+
+func TestAuthService_SendCodeEmailReleased(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockBehavior func(userRepo *mock_repository.MockUsers, worker *mock_worker.MockTaskDistributor)
+	}{
+		{
+			name: "create failed",
+			mockBehavior: func(userRepo *mock_repository.MockUsers, worker *mock_worker.MockTaskDistributor) {
+				userRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain_user.User{}, ErrInternalServerError)
+				worker.EXPECT().DistributeTaskSendVerifyEmail(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "enqueue failed",
+			mockBehavior: func(userRepo *mock_repository.MockUsers, worker *mock_worker.MockTaskDistributor) {
+				userRepo.EXPECT().Create(gomock.Any(), gomock.Any())
+				worker.EXPECT().DistributeTaskSendVerifyEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(ErrInternalServerError)
+			},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			authConfig := config.AuthConfig{
+				SendCode: config.SendCodeConfig{Cooldown: time.Minute, DailyLimitPerEmail: 5},
+			}
+			authService, userRepo, sendCodeThrottle, worker := mockSendCodeAuthService(t, authConfig)
+
+			inp := domain_auth.NewSendCodeEmailInput("email@ya.ru", "127.0.0.1")
+			params := cache.AcquireSendCodeParams{
+				Email:              inp.Email,
+				ClientIP:           inp.ClientIP,
+				Cooldown:           time.Minute,
+				DailyLimitPerEmail: 5,
+			}
+
+			sendCodeThrottle.EXPECT().Acquire(gomock.Any(), params)
+			sendCodeThrottle.EXPECT().Release(gomock.Any(), params)
+			testCase.mockBehavior(userRepo, worker)
+
+			err := authService.SendCodeEmail(context.Background(), inp)
+			require.ErrorIs(t, err, ErrInternalServerError)
+		})
+	}
+}
+
+func TestAuthService_SendCodeEmailTaskID(t *testing.T) {
+	authConfig := config.AuthConfig{
+		SendCode:  config.SendCodeConfig{Cooldown: time.Hour},
+		SecretKey: "secret_key",
+	}
+	authService, userRepo, sendCodeThrottle, worker := mockSendCodeAuthService(t, authConfig)
+
+	var taskIDs []string
+
+	sendCodeThrottle.EXPECT().Acquire(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	userRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(2)
+	worker.EXPECT().DistributeTaskSendVerifyEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ *workerpkg.PayloadSendVerifyEmail, opts ...asynq.Option) error {
+			for _, opt := range opts {
+				if opt.Type() == asynq.TaskIDOpt {
+					taskIDs = append(taskIDs, opt.Value().(string))
+				}
+			}
+
+			return nil
+		}).
+		Times(2)
+
+	for _, email := range []string{"email@ya.ru", "Email@YA.ru"} {
+		err := authService.SendCodeEmail(context.Background(), domain_auth.NewSendCodeEmailInput(email, "127.0.0.1"))
+		require.NoError(t, err)
+	}
+
+	mac := hmac.New(sha256.New, []byte("secret_key"))
+	mac.Write([]byte("email@ya.ru"))
+
+	plain := sha256.Sum256([]byte("email@ya.ru"))
+
+	require.Len(t, taskIDs, 2)
+	require.Equal(t, taskIDs[0], taskIDs[1])
+	require.Contains(t, taskIDs[0], hex.EncodeToString(mac.Sum(nil)))
+	require.NotContains(t, taskIDs[0], hex.EncodeToString(plain[:]))
+}
+
 // func TestUsersService_SendCodeEmail(t *testing.T) {
 // 	authService, _, _, verifyEmailsRepo := mockAuthService(t)
 
@@ -94,17 +262,83 @@ func mockAuthService(t *testing.T) (
 func TestUsersService_SignInErrExpiredCode(t *testing.T) {
 	authService, _, _, verifyEmailsRepo := mockAuthService(t)
 
-	// ctx := context.Background()
-	w := httptest.NewRecorder()
-	ctx, _ := gin.CreateTestContext(w)
+	ctx := context.Background()
 
-	verifyEmailsRepo.EXPECT().Get(ctx, gomock.Any())
+	verifyEmailsRepo.EXPECT().Get(gomock.Any(), gomock.Any())
 
 	res, err := authService.SignIn(ctx, domain_auth.SignInInput{})
 	require.True(t, errors.Is(err, domain.ErrSecretCodeExpired))
 	require.IsType(t, domain_auth.SignInOutput{}, res)
 }
 
+func TestUsersService_SignInClientInfo(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	secretKey, err := utils.RandomString(32)
+	require.NoError(t, err)
+
+	tokenManager, err := auth.NewJWTManager(secretKey)
+	require.NoError(t, err)
+
+	repoUsers := mock_repository.NewMockUsers(mockCtl)
+	repoSessions := mock_repository.NewMockSessions(mockCtl)
+	repoVerifyEmails := mock_repository.NewMockVerifyEmails(mockCtl)
+	worker := mock_worker.NewMockTaskDistributor(mockCtl)
+	eventPublisher := mock_event.NewMockPublisher(mockCtl)
+	authService := service.NewAuthService(
+		repoUsers,
+		repoSessions,
+		repoVerifyEmails,
+		mock_redis.NewMockSendCodeThrottle(mockCtl),
+		&hash.SHA256Hasher{},
+		tokenManager,
+		&otp.TOTPGenerator{},
+		&identity.IDGenerator{},
+		config.AuthConfig{},
+		worker,
+		eventPublisher,
+	)
+
+	client := clientinfo.Info{
+		IP:        "192.0.2.1",
+		UserAgent: "curl/8.0",
+		DeviceID:  "device",
+		RequestID: "request",
+	}
+	ctx := clientinfo.WithContext(context.Background(), client)
+	user := domain_user.User{ID: uuid.New(), Email: "email@ya.ru"}
+
+	repoVerifyEmails.EXPECT().Get(gomock.Any(), gomock.Any()).
+		Return(domain_auth.VerifyEmail{ExpiresAt: time.Now().Add(time.Minute)}, nil)
+	repoVerifyEmails.EXPECT().DeleteByID(gomock.Any(), gomock.Any())
+	repoUsers.EXPECT().GetByEmail(gomock.Any(), user.Email).Return(user, nil)
+	repoSessions.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg repository.CreateSessionParams) (domain_auth.Session, error) {
+			require.Equal(t, user.ID, arg.UserID)
+			require.Equal(t, client.IP, arg.ClientIP)
+			require.Equal(t, client.UserAgent, arg.UserAgent)
+			require.Equal(t, client.DeviceID, arg.DeviceID)
+			require.Equal(t, client.RequestID, arg.RequestID)
+
+			return domain_auth.Session{}, nil
+		})
+	eventPublisher.EXPECT().Publish(gomock.Any(), gomock.Any())
+	worker.EXPECT().
+		DistributeTaskSendLoginNotification(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, payload *workerpkg.PayloadSendLoginNotification, _ ...asynq.Option) error {
+			require.Equal(t, client.IP, payload.ClientIP)
+			require.Equal(t, client.UserAgent, payload.UserAgent)
+
+			return nil
+		})
+
+	res, err := authService.SignIn(ctx, domain_auth.NewSignInInput(user.Email, "123456"))
+	require.NoError(t, err)
+	require.NotEmpty(t, res.AccessToken)
+}
+
 // func TestUsersService_SignInErrCodeInvalid(t *testing.T) {
 // 	authService, _, _, verifyEmailsRepo := mockAuthService(t)
 
@@ -123,11 +357,9 @@ func TestUsersService_SignInErrExpiredCode(t *testing.T) {
 func TestUsersService_SignInErrGetEmail(t *testing.T) {
 	authService, _, _, verifyEmailsRepo := mockAuthService(t)
 
-	// ctx := context.Background()
-	w := httptest.NewRecorder()
-	ctx, _ := gin.CreateTestContext(w)
+	ctx := context.Background()
 
-	verifyEmailsRepo.EXPECT().Get(ctx, gomock.Any()).
+	verifyEmailsRepo.EXPECT().Get(gomock.Any(), gomock.Any()).
 		Return(domain_auth.VerifyEmail{}, ErrInternalServerError)
 
 	res, err := authService.SignIn(ctx, domain_auth.SignInInput{})
@@ -138,18 +370,16 @@ func TestUsersService_SignInErrGetEmail(t *testing.T) {
 func TestUsersService_SignInErrDeleteEmail(t *testing.T) {
 	authService, _, _, verifyEmailsRepo := mockAuthService(t)
 
-	// ctx := context.Background()
-	w := httptest.NewRecorder()
-	ctx, _ := gin.CreateTestContext(w)
+	ctx := context.Background()
 
-	verifyEmailsRepo.EXPECT().Get(ctx, gomock.Any()).
+	verifyEmailsRepo.EXPECT().Get(gomock.Any(), gomock.Any()).
 		Return(
 			domain_auth.VerifyEmail{
 				ExpiresAt: time.Now().Add(time.Minute),
 			},
 			nil,
 		)
-	verifyEmailsRepo.EXPECT().DeleteByID(ctx, gomock.Any()).Return(ErrInternalServerError)
+	verifyEmailsRepo.EXPECT().DeleteByID(gomock.Any(), gomock.Any()).Return(ErrInternalServerError)
 
 	res, err := authService.SignIn(ctx, domain_auth.SignInInput{})
 	require.True(t, errors.Is(err, ErrInternalServerError))
@@ -159,19 +389,17 @@ func TestUsersService_SignInErrDeleteEmail(t *testing.T) {
 func TestUsersService_SignInErrGetUser(t *testing.T) {
 	authService, userRepo, _, verifyEmailsRepo := mockAuthService(t)
 
-	// ctx := context.Background()
-	w := httptest.NewRecorder()
-	ctx, _ := gin.CreateTestContext(w)
+	ctx := context.Background()
 
-	verifyEmailsRepo.EXPECT().Get(ctx, gomock.Any()).
+	verifyEmailsRepo.EXPECT().Get(gomock.Any(), gomock.Any()).
 		Return(
 			domain_auth.VerifyEmail{
 				ExpiresAt: time.Now().Add(time.Minute),
 			},
 			nil,
 		)
-	verifyEmailsRepo.EXPECT().DeleteByID(ctx, gomock.Any())
-	userRepo.EXPECT().GetByEmail(ctx, gomock.Any()).
+	verifyEmailsRepo.EXPECT().DeleteByID(gomock.Any(), gomock.Any())
+	userRepo.EXPECT().GetByEmail(gomock.Any(), gomock.Any()).
 		Return(domain_user.User{}, ErrInternalServerError)
 
 	res, err := authService.SignIn(ctx, domain_auth.SignInInput{})
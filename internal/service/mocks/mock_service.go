@@ -9,8 +9,9 @@ import (
 	reflect "reflect"
 
 	auth "github.com/b0shka/backend/internal/domain/auth"
+	task "github.com/b0shka/backend/internal/domain/task"
 	user "github.com/b0shka/backend/internal/domain/user"
-	gin "github.com/gin-gonic/gin"
+	webhook "github.com/b0shka/backend/internal/domain/webhook"
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
 )
@@ -68,7 +69,7 @@ func (mr *MockAuthMockRecorder) SendCodeEmail(ctx, inp interface{}) *gomock.Call
 }
 
 // SignIn mocks base method.
-func (m *MockAuth) SignIn(ctx *gin.Context, inp auth.SignInInput) (auth.SignInOutput, error) {
+func (m *MockAuth) SignIn(ctx context.Context, inp auth.SignInInput) (auth.SignInOutput, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SignIn", ctx, inp)
 	ret0, _ := ret[0].(auth.SignInOutput)
@@ -133,3 +134,222 @@ func (mr *MockUsersMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUsers)(nil).GetByID), ctx, id)
 }
+
+// UpdateLocale mocks base method.
+func (m *MockUsers) UpdateLocale(ctx context.Context, id uuid.UUID, locale string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLocale", ctx, id, locale)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLocale indicates an expected call of UpdateLocale.
+func (mr *MockUsersMockRecorder) UpdateLocale(ctx, id, locale interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLocale", reflect.TypeOf((*MockUsers)(nil).UpdateLocale), ctx, id, locale)
+}
+
+// MockWebhooks is a mock of Webhooks interface.
+type MockWebhooks struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhooksMockRecorder
+}
+
+// MockWebhooksMockRecorder is the mock recorder for MockWebhooks.
+type MockWebhooksMockRecorder struct {
+	mock *MockWebhooks
+}
+
+// NewMockWebhooks creates a new mock instance.
+func NewMockWebhooks(ctrl *gomock.Controller) *MockWebhooks {
+	mock := &MockWebhooks{ctrl: ctrl}
+	mock.recorder = &MockWebhooksMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhooks) EXPECT() *MockWebhooksMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWebhooks) Create(ctx context.Context, inp webhook.CreateSubscriptionInput) (webhook.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, inp)
+	ret0, _ := ret[0].(webhook.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhooksMockRecorder) Create(ctx, inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhooks)(nil).Create), ctx, inp)
+}
+
+// Delete mocks base method.
+func (m *MockWebhooks) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebhooksMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebhooks)(nil).Delete), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockWebhooks) List(ctx context.Context) ([]webhook.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]webhook.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockWebhooksMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWebhooks)(nil).List), ctx)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockWebhooks) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID) ([]webhook.DeliveryWithAttempts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", ctx, subscriptionID)
+	ret0, _ := ret[0].([]webhook.DeliveryWithAttempts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockWebhooksMockRecorder) ListDeliveries(ctx, subscriptionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockWebhooks)(nil).ListDeliveries), ctx, subscriptionID)
+}
+
+// Redeliver mocks base method.
+func (m *MockWebhooks) Redeliver(ctx context.Context, subscriptionID, deliveryID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeliver", ctx, subscriptionID, deliveryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Redeliver indicates an expected call of Redeliver.
+func (mr *MockWebhooksMockRecorder) Redeliver(ctx, subscriptionID, deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeliver", reflect.TypeOf((*MockWebhooks)(nil).Redeliver), ctx, subscriptionID, deliveryID)
+}
+
+// MockTasks is a mock of Tasks interface.
+type MockTasks struct {
+	ctrl     *gomock.Controller
+	recorder *MockTasksMockRecorder
+}
+
+// MockTasksMockRecorder is the mock recorder for MockTasks.
+type MockTasksMockRecorder struct {
+	mock *MockTasks
+}
+
+// NewMockTasks creates a new mock instance.
+func NewMockTasks(ctrl *gomock.Controller) *MockTasks {
+	mock := &MockTasks{ctrl: ctrl}
+	mock.recorder = &MockTasksMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTasks) EXPECT() *MockTasksMockRecorder {
+	return m.recorder
+}
+
+// DeleteTask mocks base method.
+func (m *MockTasks) DeleteTask(ctx context.Context, queue, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTask", ctx, queue, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTask indicates an expected call of DeleteTask.
+func (mr *MockTasksMockRecorder) DeleteTask(ctx, queue, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockTasks)(nil).DeleteTask), ctx, queue, id)
+}
+
+// ListQueues mocks base method.
+func (m *MockTasks) ListQueues(ctx context.Context) ([]task.Queue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListQueues", ctx)
+	ret0, _ := ret[0].([]task.Queue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListQueues indicates an expected call of ListQueues.
+func (mr *MockTasksMockRecorder) ListQueues(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListQueues", reflect.TypeOf((*MockTasks)(nil).ListQueues), ctx)
+}
+
+// ListTasks mocks base method.
+func (m *MockTasks) ListTasks(ctx context.Context, inp task.ListTasksInput) ([]task.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTasks", ctx, inp)
+	ret0, _ := ret[0].([]task.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTasks indicates an expected call of ListTasks.
+func (mr *MockTasksMockRecorder) ListTasks(ctx, inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasks", reflect.TypeOf((*MockTasks)(nil).ListTasks), ctx, inp)
+}
+
+// PauseQueue mocks base method.
+func (m *MockTasks) PauseQueue(ctx context.Context, queue string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseQueue", ctx, queue)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseQueue indicates an expected call of PauseQueue.
+func (mr *MockTasksMockRecorder) PauseQueue(ctx, queue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseQueue", reflect.TypeOf((*MockTasks)(nil).PauseQueue), ctx, queue)
+}
+
+// ResumeQueue mocks base method.
+func (m *MockTasks) ResumeQueue(ctx context.Context, queue string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeQueue", ctx, queue)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeQueue indicates an expected call of ResumeQueue.
+func (mr *MockTasksMockRecorder) ResumeQueue(ctx, queue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeQueue", reflect.TypeOf((*MockTasks)(nil).ResumeQueue), ctx, queue)
+}
+
+// RunTask mocks base method.
+func (m *MockTasks) RunTask(ctx context.Context, queue, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunTask", ctx, queue, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunTask indicates an expected call of RunTask.
+func (mr *MockTasksMockRecorder) RunTask(ctx, queue, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunTask", reflect.TypeOf((*MockTasks)(nil).RunTask), ctx, queue, id)
+}
@@ -5,60 +5,130 @@ import (
 
 	"github.com/b0shka/backend/internal/config"
 	domain_auth "github.com/b0shka/backend/internal/domain/auth"
+	domain_task "github.com/b0shka/backend/internal/domain/task"
 	domain_user "github.com/b0shka/backend/internal/domain/user"
+	domain_webhook "github.com/b0shka/backend/internal/domain/webhook"
+	"github.com/b0shka/backend/internal/event"
 	repository "github.com/b0shka/backend/internal/repository/postgresql"
+	cache "github.com/b0shka/backend/internal/repository/redis"
 	"github.com/b0shka/backend/internal/worker"
 	"github.com/b0shka/backend/pkg/auth"
 	"github.com/b0shka/backend/pkg/hash"
+	"github.com/b0shka/backend/pkg/i18n"
 	"github.com/b0shka/backend/pkg/identity"
+	"github.com/b0shka/backend/pkg/logger"
 	"github.com/b0shka/backend/pkg/otp"
-	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const tracerName = "github.com/b0shka/backend/internal/service"
+
 type Auth interface {
 	SendCodeEmail(ctx context.Context, inp domain_auth.SendCodeEmailInput) error
-	SignIn(ctx *gin.Context, inp domain_auth.SignInInput) (domain_auth.SignInOutput, error)
+	SignIn(ctx context.Context, inp domain_auth.SignInInput) (domain_auth.SignInOutput, error)
 	RefreshToken(ctx context.Context, inp domain_auth.RefreshTokenInput) (domain_auth.RefreshTokenOutput, error)
 }
 
 type Users interface {
 	GetByID(ctx context.Context, id uuid.UUID) (domain_user.User, error)
+	UpdateLocale(ctx context.Context, id uuid.UUID, locale string) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type Webhooks interface {
+	Create(ctx context.Context, inp domain_webhook.CreateSubscriptionInput) (domain_webhook.Subscription, error)
+	List(ctx context.Context) ([]domain_webhook.Subscription, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	ListDeliveries(ctx context.Context, subscriptionID uuid.UUID) ([]domain_webhook.DeliveryWithAttempts, error)
+	Redeliver(ctx context.Context, subscriptionID, deliveryID uuid.UUID) error
+}
+
+type Tasks interface {
+	ListQueues(ctx context.Context) ([]domain_task.Queue, error)
+	ListTasks(ctx context.Context, inp domain_task.ListTasksInput) ([]domain_task.Task, error)
+	RunTask(ctx context.Context, queue, id string) error
+	DeleteTask(ctx context.Context, queue, id string) error
+	PauseQueue(ctx context.Context, queue string) error
+	ResumeQueue(ctx context.Context, queue string) error
 }
 
 type Services struct {
 	Auth
 	Users
+	Webhooks
+	Tasks
 }
 
 type Deps struct {
 	Repos           *repository.Repositories
+	CacheRepos      *cache.Repositories
 	Hasher          hash.Hasher
 	TokenManager    auth.Manager
 	OTPGenerator    otp.Generator
 	IDGenerator     identity.Generator
 	AuthConfig      config.AuthConfig
+	WebhooksConfig  config.WebhooksConfig
 	TaskDistributor worker.TaskDistributor
+	TaskInspector   worker.TaskInspector
+	EventPublisher  event.Publisher
+	I18n            *i18n.Bundle
 }
 
 func NewServices(deps Deps) *Services {
+	webhooksService := NewWebhooksService(
+		deps.Repos.WebhookSubscriptions,
+		deps.Repos.WebhookDeliveries,
+		deps.IDGenerator,
+		deps.WebhooksConfig,
+		deps.TaskDistributor,
+	)
+	eventPublisher := event.NewMultiPublisher(deps.EventPublisher, webhooksService)
+
 	return &Services{
 		Auth: NewAuthService(
 			deps.Repos.Users,
 			deps.Repos.Sessions,
 			deps.Repos.VerifyEmails,
+			deps.CacheRepos.SendCodeThrottle,
 			deps.Hasher,
 			deps.TokenManager,
 			deps.OTPGenerator,
 			deps.IDGenerator,
 			deps.AuthConfig,
 			deps.TaskDistributor,
+			eventPublisher,
 		),
 		Users: NewUsersService(
 			deps.Repos.Users,
 			deps.Repos.Sessions,
 			deps.Repos.VerifyEmails,
+			eventPublisher,
+			deps.I18n,
 		),
+		Webhooks: webhooksService,
+		Tasks:    NewTasksService(deps.TaskInspector),
+	}
+}
+
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name)
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// publishEvent delivers a domain event on a best-effort basis: the operation
+// that produced the event has already been committed, so a broker failure
+// is only logged.
+func publishEvent(ctx context.Context, publisher event.Publisher, e event.Event) {
+	if err := publisher.Publish(ctx, e); err != nil {
+		logger.FromContext(ctx).Error("failed to publish event", "type", e.Type, "id", e.ID, logger.Err(err))
 	}
 }
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+
+	"github.com/b0shka/backend/internal/domain"
+	domain_task "github.com/b0shka/backend/internal/domain/task"
+	"github.com/b0shka/backend/internal/worker"
+)
+
+type TasksService struct {
+	inspector worker.TaskInspector
+}
+
+// NewTasksService accepts a nil inspector for the worker backends that cannot
+// be inspected, every call then fails with domain.ErrInspectorUnsupported.
+func NewTasksService(inspector worker.TaskInspector) *TasksService {
+	return &TasksService{
+		inspector: inspector,
+	}
+}
+
+func (s *TasksService) ListQueues(_ context.Context) ([]domain_task.Queue, error) {
+	if s.inspector == nil {
+		return nil, domain.ErrInspectorUnsupported
+	}
+
+	return s.inspector.Queues()
+}
+
+func (s *TasksService) ListTasks(_ context.Context, inp domain_task.ListTasksInput) ([]domain_task.Task, error) {
+	if s.inspector == nil {
+		return nil, domain.ErrInspectorUnsupported
+	}
+
+	return s.inspector.ListTasks(inp)
+}
+
+func (s *TasksService) RunTask(_ context.Context, queue, id string) error {
+	if s.inspector == nil {
+		return domain.ErrInspectorUnsupported
+	}
+
+	return s.inspector.RunTask(queue, id)
+}
+
+func (s *TasksService) DeleteTask(_ context.Context, queue, id string) error {
+	if s.inspector == nil {
+		return domain.ErrInspectorUnsupported
+	}
+
+	return s.inspector.DeleteTask(queue, id)
+}
+
+func (s *TasksService) PauseQueue(_ context.Context, queue string) error {
+	if s.inspector == nil {
+		return domain.ErrInspectorUnsupported
+	}
+
+	return s.inspector.PauseQueue(queue)
+}
+
+func (s *TasksService) ResumeQueue(_ context.Context, queue string) error {
+	if s.inspector == nil {
+		return domain.ErrInspectorUnsupported
+	}
+
+	return s.inspector.UnpauseQueue(queue)
+}
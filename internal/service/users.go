@@ -2,9 +2,15 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
+	"github.com/b0shka/backend/internal/domain"
 	domain_user "github.com/b0shka/backend/internal/domain/user"
+	"github.com/b0shka/backend/internal/event"
 	repository "github.com/b0shka/backend/internal/repository/postgresql"
+	sqlc "github.com/b0shka/backend/internal/repository/postgresql/sqlc"
+	"github.com/b0shka/backend/pkg/i18n"
 	"github.com/google/uuid"
 )
 
@@ -12,22 +18,43 @@ type UsersService struct {
 	repoUsers        repository.Users
 	repoSessions     repository.Sessions
 	repoVerifyEmails repository.VerifyEmails
+	eventPublisher   event.Publisher
+	i18n             *i18n.Bundle
 }
 
 func NewUsersService(
 	repoUsers repository.Users,
 	repoSessions repository.Sessions,
 	repoVerifyEmails repository.VerifyEmails,
+	eventPublisher event.Publisher,
+	bundle *i18n.Bundle,
 ) *UsersService {
 	return &UsersService{
 		repoUsers:        repoUsers,
 		repoSessions:     repoSessions,
 		repoVerifyEmails: repoVerifyEmails,
+		eventPublisher:   eventPublisher,
+		i18n:             bundle,
 	}
 }
 
 func (s *UsersService) GetByID(ctx context.Context, id uuid.UUID) (domain_user.User, error) {
-	return s.repoUsers.GetByID(ctx, id)
+	user, err := s.repoUsers.GetByID(ctx, id)
+	if errors.Is(err, sqlc.ErrRecordNotFound) {
+		return domain_user.User{}, domain.ErrUserNotFound
+	}
+
+	return user, err
+}
+
+// UpdateLocale saves the locale the user prefers, it wins over the
+// Accept-Language header of the requests when the emails are rendered.
+func (s *UsersService) UpdateLocale(ctx context.Context, id uuid.UUID, locale string) error {
+	if !s.i18n.Supports(locale) {
+		return fmt.Errorf("%w: %s", domain.ErrUnsupportedLocale, locale)
+	}
+
+	return s.repoUsers.UpdateLocale(ctx, id, locale)
 }
 
 func (s *UsersService) Delete(ctx context.Context, id uuid.UUID) error {
@@ -36,8 +63,16 @@ func (s *UsersService) Delete(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 
+	publishEvent(ctx, s.eventPublisher, event.New(event.TypeSessionRevoked, event.SessionRevoked{
+		UserID: id,
+	}))
+
 	user, err := s.repoUsers.GetByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, sqlc.ErrRecordNotFound) {
+			return domain.ErrUserNotFound
+		}
+
 		return err
 	}
 
@@ -46,5 +81,14 @@ func (s *UsersService) Delete(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 
-	return s.repoUsers.Delete(ctx, id)
+	if err = s.repoUsers.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	publishEvent(ctx, s.eventPublisher, event.New(event.TypeUserDeleted, event.UserDeleted{
+		UserID: user.ID,
+		Email:  user.Email,
+	}))
+
+	return nil
 }
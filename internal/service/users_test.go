@@ -5,9 +5,14 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/b0shka/backend/internal/domain"
 	domain_user "github.com/b0shka/backend/internal/domain/user"
+	"github.com/b0shka/backend/internal/event"
+	mock_event "github.com/b0shka/backend/internal/event/mocks"
 	mock_repository "github.com/b0shka/backend/internal/repository/postgresql/mocks"
+	repository "github.com/b0shka/backend/internal/repository/postgresql/sqlc"
 	"github.com/b0shka/backend/internal/service"
+	"github.com/b0shka/backend/pkg/i18n"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -18,6 +23,7 @@ func mockUserService(t *testing.T) (
 	*mock_repository.MockUsers,
 	*mock_repository.MockSessions,
 	*mock_repository.MockVerifyEmails,
+	*mock_event.MockPublisher,
 ) {
 	repoCtl := gomock.NewController(t)
 	defer repoCtl.Finish()
@@ -25,20 +31,30 @@ func mockUserService(t *testing.T) (
 	workerCtl := gomock.NewController(t)
 	defer workerCtl.Finish()
 
+	eventCtl := gomock.NewController(t)
+	defer eventCtl.Finish()
+
 	repoUsers := mock_repository.NewMockUsers(repoCtl)
 	repoSessions := mock_repository.NewMockSessions(repoCtl)
 	repoVerifyEmails := mock_repository.NewMockVerifyEmails(repoCtl)
+	eventPublisher := mock_event.NewMockPublisher(eventCtl)
+
+	bundle, err := i18n.NewBundle("en", map[string]i18n.Catalog{"en": {}, "ru": {}})
+	require.NoError(t, err)
+
 	userService := service.NewUsersService(
 		repoUsers,
 		repoSessions,
 		repoVerifyEmails,
+		eventPublisher,
+		bundle,
 	)
 
-	return userService, repoUsers, repoSessions, repoVerifyEmails
+	return userService, repoUsers, repoSessions, repoVerifyEmails, eventPublisher
 }
 
 func TestUsersService_Get(t *testing.T) {
-	userService, userRepo, _, _ := mockUserService(t)
+	userService, userRepo, _, _, _ := mockUserService(t)
 
 	ctx := context.Background()
 	userRepo.EXPECT().GetByID(ctx, gomock.Any())
@@ -49,7 +65,7 @@ func TestUsersService_Get(t *testing.T) {
 }
 
 func TestUsersService_GetErr(t *testing.T) {
-	userService, userRepo, _, _ := mockUserService(t)
+	userService, userRepo, _, _, _ := mockUserService(t)
 
 	ctx := context.Background()
 	userRepo.EXPECT().GetByID(ctx, gomock.Any()).Return(domain_user.User{}, ErrInternalServerError)
@@ -59,8 +75,18 @@ func TestUsersService_GetErr(t *testing.T) {
 	require.IsType(t, domain_user.User{}, res)
 }
 
+func TestUsersService_GetNotFound(t *testing.T) {
+	userService, userRepo, _, _, _ := mockUserService(t)
+
+	ctx := context.Background()
+	userRepo.EXPECT().GetByID(ctx, gomock.Any()).Return(domain_user.User{}, repository.ErrRecordNotFound)
+
+	_, err := userService.GetByID(ctx, uuid.UUID{})
+	require.ErrorIs(t, err, domain.ErrUserNotFound)
+}
+
 func TestUsersService_Delete(t *testing.T) {
-	userService, userRepo, sessionRepo, verifyEmailsRepo := mockUserService(t)
+	userService, userRepo, sessionRepo, verifyEmailsRepo, eventPublisher := mockUserService(t)
 
 	ctx := context.Background()
 	sessionRepo.EXPECT().Delete(ctx, gomock.Any())
@@ -68,12 +94,21 @@ func TestUsersService_Delete(t *testing.T) {
 	verifyEmailsRepo.EXPECT().DeleteByEmail(ctx, gomock.Any())
 	userRepo.EXPECT().Delete(ctx, gomock.Any())
 
+	var eventTypes []string
+
+	eventPublisher.EXPECT().Publish(ctx, gomock.Any()).
+		Do(func(_ context.Context, e event.Event) {
+			eventTypes = append(eventTypes, e.Type)
+		}).
+		Times(2)
+
 	err := userService.Delete(ctx, uuid.UUID{})
 	require.NoError(t, err)
+	require.Equal(t, []string{event.TypeSessionRevoked, event.TypeUserDeleted}, eventTypes)
 }
 
 func TestUsersService_DeleteErrDelSession(t *testing.T) {
-	userService, _, sessionRepo, _ := mockUserService(t)
+	userService, _, sessionRepo, _, _ := mockUserService(t)
 
 	ctx := context.Background()
 	sessionRepo.EXPECT().Delete(ctx, gomock.Any()).Return(ErrInternalServerError)
@@ -83,10 +118,11 @@ func TestUsersService_DeleteErrDelSession(t *testing.T) {
 }
 
 func TestUsersService_DeleteErrGetUser(t *testing.T) {
-	userService, userRepo, sessionRepo, _ := mockUserService(t)
+	userService, userRepo, sessionRepo, _, eventPublisher := mockUserService(t)
 
 	ctx := context.Background()
 	sessionRepo.EXPECT().Delete(ctx, gomock.Any())
+	eventPublisher.EXPECT().Publish(ctx, gomock.Any())
 	userRepo.EXPECT().GetByID(ctx, gomock.Any()).
 		Return(domain_user.User{}, ErrInternalServerError)
 
@@ -95,10 +131,11 @@ func TestUsersService_DeleteErrGetUser(t *testing.T) {
 }
 
 func TestUsersService_DeleteErrDelVerEmail(t *testing.T) {
-	userService, userRepo, sessionRepo, verifyEmailsRepo := mockUserService(t)
+	userService, userRepo, sessionRepo, verifyEmailsRepo, eventPublisher := mockUserService(t)
 
 	ctx := context.Background()
 	sessionRepo.EXPECT().Delete(ctx, gomock.Any())
+	eventPublisher.EXPECT().Publish(ctx, gomock.Any())
 	userRepo.EXPECT().GetByID(ctx, gomock.Any())
 	verifyEmailsRepo.EXPECT().DeleteByEmail(ctx, gomock.Any()).Return(ErrInternalServerError)
 
@@ -107,10 +144,11 @@ func TestUsersService_DeleteErrDelVerEmail(t *testing.T) {
 }
 
 func TestUsersService_DeleteErrDelUser(t *testing.T) {
-	userService, userRepo, sessionRepo, verifyEmailsRepo := mockUserService(t)
+	userService, userRepo, sessionRepo, verifyEmailsRepo, eventPublisher := mockUserService(t)
 
 	ctx := context.Background()
 	sessionRepo.EXPECT().Delete(ctx, gomock.Any())
+	eventPublisher.EXPECT().Publish(ctx, gomock.Any())
 	userRepo.EXPECT().GetByID(ctx, gomock.Any())
 	verifyEmailsRepo.EXPECT().DeleteByEmail(ctx, gomock.Any())
 	userRepo.EXPECT().Delete(ctx, gomock.Any()).Return(ErrInternalServerError)
@@ -118,3 +156,23 @@ func TestUsersService_DeleteErrDelUser(t *testing.T) {
 	err := userService.Delete(ctx, uuid.UUID{})
 	require.True(t, errors.Is(err, ErrInternalServerError))
 }
+
+func TestUsersService_UpdateLocale(t *testing.T) {
+	userService, userRepo, _, _, _ := mockUserService(t)
+
+	ctx := context.Background()
+	userRepo.EXPECT().UpdateLocale(ctx, gomock.Any(), "ru")
+
+	err := userService.UpdateLocale(ctx, uuid.UUID{}, "ru")
+	require.NoError(t, err)
+}
+
+func TestUsersService_UpdateLocaleUnsupported(t *testing.T) {
+	userService, userRepo, _, _, _ := mockUserService(t)
+
+	ctx := context.Background()
+	userRepo.EXPECT().UpdateLocale(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	err := userService.UpdateLocale(ctx, uuid.UUID{}, "de")
+	require.ErrorIs(t, err, domain.ErrUnsupportedLocale)
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/b0shka/backend/internal/config"
+	"github.com/b0shka/backend/internal/domain"
+	domain_webhook "github.com/b0shka/backend/internal/domain/webhook"
+	"github.com/b0shka/backend/internal/event"
+	repository "github.com/b0shka/backend/internal/repository/postgresql"
+	sqlc "github.com/b0shka/backend/internal/repository/postgresql/sqlc"
+	"github.com/b0shka/backend/internal/worker"
+	"github.com/b0shka/backend/pkg/identity"
+	"github.com/b0shka/backend/pkg/requestid"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const deliveriesListLimit = 50
+
+type WebhooksService struct {
+	repoSubscriptions repository.WebhookSubscriptions
+	repoDeliveries    repository.WebhookDeliveries
+	idGenerator       identity.Generator
+	webhooksConfig    config.WebhooksConfig
+	taskDistributor   worker.TaskDistributor
+}
+
+func NewWebhooksService(
+	repoSubscriptions repository.WebhookSubscriptions,
+	repoDeliveries repository.WebhookDeliveries,
+	idGenerator identity.Generator,
+	webhooksConfig config.WebhooksConfig,
+	taskDistributor worker.TaskDistributor,
+) *WebhooksService {
+	return &WebhooksService{
+		repoSubscriptions: repoSubscriptions,
+		repoDeliveries:    repoDeliveries,
+		idGenerator:       idGenerator,
+		webhooksConfig:    webhooksConfig,
+		taskDistributor:   taskDistributor,
+	}
+}
+
+func (s *WebhooksService) Create(
+	ctx context.Context,
+	inp domain_webhook.CreateSubscriptionInput,
+) (domain_webhook.Subscription, error) {
+	// the deliveries are signed, not encrypted, and the worker only follows
+	// the https endpoints to public addresses.
+	if endpoint, err := url.Parse(inp.URL); err != nil || endpoint.Scheme != "https" {
+		return domain_webhook.Subscription{}, domain.ErrInsecureWebhookURL
+	}
+
+	for _, eventType := range inp.EventTypes {
+		if !isKnownEventType(eventType) {
+			return domain_webhook.Subscription{}, fmt.Errorf("%w: %s", domain.ErrUnknownEventType, eventType)
+		}
+	}
+
+	subscriptionParams := repository.CreateWebhookSubscriptionParams{
+		ID:         s.idGenerator.GenerateUUID(),
+		URL:        inp.URL,
+		Secret:     inp.Secret,
+		EventTypes: inp.EventTypes,
+	}
+
+	return s.repoSubscriptions.Create(ctx, subscriptionParams)
+}
+
+func (s *WebhooksService) List(ctx context.Context) ([]domain_webhook.Subscription, error) {
+	return s.repoSubscriptions.List(ctx)
+}
+
+func (s *WebhooksService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.subscriptionExists(ctx, id); err != nil {
+		return err
+	}
+
+	return s.repoSubscriptions.Delete(ctx, id)
+}
+
+func (s *WebhooksService) ListDeliveries(
+	ctx context.Context,
+	subscriptionID uuid.UUID,
+) ([]domain_webhook.DeliveryWithAttempts, error) {
+	if err := s.subscriptionExists(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.repoDeliveries.ListBySubscription(ctx, subscriptionID, deliveriesListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]domain_webhook.DeliveryWithAttempts, 0, len(deliveries))
+
+	for _, delivery := range deliveries {
+		attempts, err := s.repoDeliveries.ListAttempts(ctx, delivery.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, domain_webhook.DeliveryWithAttempts{
+			Delivery:    delivery,
+			AttemptsLog: attempts,
+		})
+	}
+
+	return res, nil
+}
+
+func (s *WebhooksService) Redeliver(ctx context.Context, subscriptionID, deliveryID uuid.UUID) error {
+	delivery, err := s.repoDeliveries.Get(ctx, deliveryID)
+	if err != nil {
+		if errors.Is(err, sqlc.ErrRecordNotFound) {
+			return domain.ErrWebhookDeliveryNotFound
+		}
+
+		return err
+	}
+
+	if delivery.SubscriptionID != subscriptionID {
+		return domain.ErrWebhookDeliveryNotFound
+	}
+
+	err = s.repoDeliveries.UpdateStatus(ctx, delivery.ID, domain_webhook.DeliveryStatusPending)
+	if err != nil {
+		return err
+	}
+
+	return s.distributeDelivery(ctx, delivery.ID)
+}
+
+// Publish implements event.Publisher: every active subscription to the event
+// type gets its own delivery that is sent by the worker.
+func (s *WebhooksService) Publish(ctx context.Context, e event.Event) error {
+	subscriptions, err := s.repoSubscriptions.ListActiveByEvent(ctx, e.Type)
+	if err != nil {
+		return err
+	}
+
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, subscription := range subscriptions {
+		deliveryParams := repository.CreateWebhookDeliveryParams{
+			ID:             s.idGenerator.GenerateUUID(),
+			SubscriptionID: subscription.ID,
+			EventID:        e.ID,
+			EventType:      e.Type,
+			Payload:        payload,
+			RequestID:      requestid.FromContext(ctx),
+		}
+
+		delivery, err := s.repoDeliveries.Create(ctx, deliveryParams)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		if err := s.distributeDelivery(ctx, delivery.ID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *WebhooksService) distributeDelivery(ctx context.Context, deliveryID uuid.UUID) error {
+	taskPayload := &worker.PayloadDeliverWebhook{
+		DeliveryID: deliveryID,
+	}
+	opts := []asynq.Option{
+		asynq.MaxRetry(s.webhooksConfig.MaxRetry),
+		asynq.Queue(worker.QueueDefault),
+	}
+
+	return s.taskDistributor.DistributeTaskDeliverWebhook(ctx, taskPayload, opts...)
+}
+
+func (s *WebhooksService) subscriptionExists(ctx context.Context, id uuid.UUID) error {
+	_, err := s.repoSubscriptions.Get(ctx, id)
+	if errors.Is(err, sqlc.ErrRecordNotFound) {
+		return domain.ErrWebhookNotFound
+	}
+
+	return err
+}
+
+func isKnownEventType(eventType string) bool {
+	for _, t := range event.Types() {
+		if t == eventType {
+			return true
+		}
+	}
+
+	return false
+}
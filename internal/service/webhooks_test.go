@@ -0,0 +1,140 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/b0shka/backend/internal/config"
+	"github.com/b0shka/backend/internal/domain"
+	domain_webhook "github.com/b0shka/backend/internal/domain/webhook"
+	"github.com/b0shka/backend/internal/event"
+	repository "github.com/b0shka/backend/internal/repository/postgresql"
+	mock_repository "github.com/b0shka/backend/internal/repository/postgresql/mocks"
+	sqlc "github.com/b0shka/backend/internal/repository/postgresql/sqlc"
+	"github.com/b0shka/backend/internal/service"
+	mock_worker "github.com/b0shka/backend/internal/worker/mocks"
+	"github.com/b0shka/backend/pkg/identity"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func mockWebhooksService(t *testing.T) (
+	*service.WebhooksService,
+	*mock_repository.MockWebhookSubscriptions,
+	*mock_repository.MockWebhookDeliveries,
+	*mock_worker.MockTaskDistributor,
+) {
+	repoCtl := gomock.NewController(t)
+	defer repoCtl.Finish()
+
+	workerCtl := gomock.NewController(t)
+	defer workerCtl.Finish()
+
+	repoSubscriptions := mock_repository.NewMockWebhookSubscriptions(repoCtl)
+	repoDeliveries := mock_repository.NewMockWebhookDeliveries(repoCtl)
+	taskDistributor := mock_worker.NewMockTaskDistributor(workerCtl)
+	webhooksService := service.NewWebhooksService(
+		repoSubscriptions,
+		repoDeliveries,
+		&identity.IDGenerator{},
+		config.WebhooksConfig{MaxRetry: 8},
+		taskDistributor,
+	)
+
+	return webhooksService, repoSubscriptions, repoDeliveries, taskDistributor
+}
+
+func TestWebhooksService_CreateUnknownEventType(t *testing.T) {
+	webhooksService, _, _, _ := mockWebhooksService(t)
+
+	ctx := context.Background()
+	inp := domain_webhook.NewCreateSubscriptionInput(
+		"https://example.com/hook",
+		"0123456789abcdef",
+		[]string{event.TypeUserCreated, "user.unknown"},
+	)
+
+	_, err := webhooksService.Create(ctx, inp)
+	require.True(t, errors.Is(err, domain.ErrUnknownEventType))
+}
+
+func TestWebhooksService_CreateInsecureURL(t *testing.T) {
+	webhooksService, subscriptionRepo, _, _ := mockWebhooksService(t)
+
+	subscriptionRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+	inp := domain_webhook.NewCreateSubscriptionInput(
+		"http://example.com/hook",
+		"0123456789abcdef",
+		[]string{event.TypeUserCreated},
+	)
+
+	_, err := webhooksService.Create(context.Background(), inp)
+	require.True(t, errors.Is(err, domain.ErrInsecureWebhookURL))
+}
+
+func TestWebhooksService_Publish(t *testing.T) {
+	webhooksService, subscriptionRepo, deliveryRepo, taskDistributor := mockWebhooksService(t)
+
+	ctx := context.Background()
+	e := event.New(event.TypeUserCreated, event.UserCreated{UserID: uuid.New()})
+	subscriptions := []domain_webhook.Subscription{
+		{ID: uuid.New()},
+		{ID: uuid.New()},
+	}
+
+	subscriptionRepo.EXPECT().ListActiveByEvent(ctx, event.TypeUserCreated).Return(subscriptions, nil)
+
+	var subscriptionIDs []uuid.UUID
+
+	deliveryRepo.EXPECT().Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg repository.CreateWebhookDeliveryParams) (domain_webhook.Delivery, error) {
+			require.Equal(t, e.ID, arg.EventID)
+			require.Equal(t, e.Type, arg.EventType)
+			require.NotEmpty(t, arg.Payload)
+
+			subscriptionIDs = append(subscriptionIDs, arg.SubscriptionID)
+
+			return domain_webhook.Delivery{ID: arg.ID, SubscriptionID: arg.SubscriptionID}, nil
+		}).
+		Times(2)
+	taskDistributor.EXPECT().DistributeTaskDeliverWebhook(ctx, gomock.Any(), gomock.Any()).Times(2)
+
+	err := webhooksService.Publish(ctx, e)
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{subscriptions[0].ID, subscriptions[1].ID}, subscriptionIDs)
+}
+
+func TestWebhooksService_PublishNoSubscriptions(t *testing.T) {
+	webhooksService, subscriptionRepo, _, _ := mockWebhooksService(t)
+
+	ctx := context.Background()
+	subscriptionRepo.EXPECT().ListActiveByEvent(ctx, event.TypeUserDeleted)
+
+	err := webhooksService.Publish(ctx, event.New(event.TypeUserDeleted, event.UserDeleted{}))
+	require.NoError(t, err)
+}
+
+func TestWebhooksService_RedeliverMismatchedSubscription(t *testing.T) {
+	webhooksService, _, deliveryRepo, _ := mockWebhooksService(t)
+
+	ctx := context.Background()
+	deliveryRepo.EXPECT().Get(ctx, gomock.Any()).
+		Return(domain_webhook.Delivery{SubscriptionID: uuid.New()}, nil)
+
+	err := webhooksService.Redeliver(ctx, uuid.New(), uuid.New())
+	require.True(t, errors.Is(err, domain.ErrWebhookDeliveryNotFound))
+}
+
+func TestWebhooksService_DeleteNotFound(t *testing.T) {
+	webhooksService, subscriptionRepo, _, _ := mockWebhooksService(t)
+
+	ctx := context.Background()
+	subscriptionRepo.EXPECT().Get(ctx, gomock.Any()).
+		Return(domain_webhook.Subscription{}, sqlc.ErrRecordNotFound)
+
+	err := webhooksService.Delete(ctx, uuid.New())
+	require.ErrorIs(t, err, domain.ErrWebhookNotFound)
+}
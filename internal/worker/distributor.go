@@ -2,10 +2,20 @@ package worker
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/b0shka/backend/pkg/broker/rabbitmq"
+	"github.com/b0shka/backend/pkg/logger"
+	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
+	"github.com/streadway/amqp"
 )
 
+// TaskDistributor enqueues the tasks with the asynq options. The redis backend
+// supports all of them. The rabbitmq backend supports Queue, TaskID, MaxRetry,
+// Timeout, ProcessIn and ProcessAt and rejects the others with
+// domain.ErrUnsupportedTaskOption. The task ID is only deduplicated by the
+// redis backend, rabbitmq uses it as the message ID.
 type TaskDistributor interface {
 	DistributeTaskSendVerifyEmail(
 		ctx context.Context,
@@ -17,6 +27,17 @@ type TaskDistributor interface {
 		payload *PayloadSendLoginNotification,
 		opts ...asynq.Option,
 	) error
+	DistributeTaskDeliverWebhook(
+		ctx context.Context,
+		payload *PayloadDeliverWebhook,
+		opts ...asynq.Option,
+	) error
+	DistributeTaskPurgeExpired(
+		ctx context.Context,
+		payload *PayloadPurgeExpired,
+		opts ...asynq.Option,
+	) error
+	Close() error
 }
 
 type RedisTaskDistributor struct {
@@ -30,3 +51,98 @@ func NewRedisTaskDistributor(redisOpt asynq.RedisClientOpt) TaskDistributor {
 		client: client,
 	}
 }
+
+// Close closes the redis connections of the client.
+func (distributor *RedisTaskDistributor) Close() error {
+	return distributor.client.Close()
+}
+
+func (distributor *RedisTaskDistributor) enqueue(
+	ctx context.Context,
+	taskType string,
+	payload any,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := marshalPayload(ctx, payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(taskType, jsonPayload, opts...)
+
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	logger.FromContext(ctx).Info("enqueued task",
+		logger.KeyTaskType, task.Type(), logger.KeyTaskID, info.ID,
+		"payload", payload, "queue", info.Queue, "max_retry", info.MaxRetry)
+
+	return nil
+}
+
+type RabbitMQTaskDistributor struct {
+	publisher *rabbitmq.Publisher
+	topology  rabbitMQTopology
+}
+
+func NewRabbitMQTaskDistributor(client *rabbitmq.Client, exchange string) TaskDistributor {
+	topology := newRabbitMQTopology(exchange)
+
+	return &RabbitMQTaskDistributor{
+		publisher: rabbitmq.NewPublisher(client, topology.declare),
+		topology:  topology,
+	}
+}
+
+// Close closes the channel of the publisher, the connection is owned by the
+// client.
+func (distributor *RabbitMQTaskDistributor) Close() error {
+	return distributor.publisher.Close()
+}
+
+func (distributor *RabbitMQTaskDistributor) enqueue(
+	ctx context.Context,
+	taskType string,
+	payload any,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := marshalPayload(ctx, payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	options, err := composeTaskOptions(opts...)
+	if err != nil {
+		return err
+	}
+
+	if options.taskID == "" {
+		options.taskID = uuid.NewString()
+	}
+
+	msg := amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    options.taskID,
+		Type:         taskType,
+		Body:         jsonPayload,
+		Headers: amqp.Table{
+			headerMaxRetry: int64(options.maxRetry),
+			headerRetried:  int64(0),
+			headerTimeout:  options.timeout.Milliseconds(),
+		},
+	}
+
+	err = distributor.topology.publish(ctx, distributor.publisher, options.queue, options.processIn, msg)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	logger.FromContext(ctx).Info("enqueued task",
+		logger.KeyTaskType, taskType, logger.KeyTaskID, options.taskID,
+		"payload", payload, "queue", options.queue, "max_retry", options.maxRetry)
+
+	return nil
+}
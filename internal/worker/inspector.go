@@ -0,0 +1,190 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/b0shka/backend/internal/domain"
+	domain_task "github.com/b0shka/backend/internal/domain/task"
+	"github.com/b0shka/backend/pkg/redact"
+	"github.com/hibiken/asynq"
+)
+
+// TaskInspector gives access to the tasks held by the redis backend. Task
+// payloads and errors are redacted, so they can be shown to operators.
+type TaskInspector interface {
+	Queues() ([]domain_task.Queue, error)
+	ListTasks(inp domain_task.ListTasksInput) ([]domain_task.Task, error)
+	RunTask(queue, id string) error
+	DeleteTask(queue, id string) error
+	PauseQueue(queue string) error
+	UnpauseQueue(queue string) error
+	Close() error
+}
+
+type RedisTaskInspector struct {
+	inspector *asynq.Inspector
+}
+
+func NewRedisTaskInspector(redisOpt asynq.RedisClientOpt) TaskInspector {
+	return &RedisTaskInspector{
+		inspector: asynq.NewInspector(redisOpt),
+	}
+}
+
+// Close closes the redis connections of the inspector.
+func (i *RedisTaskInspector) Close() error {
+	return i.inspector.Close()
+}
+
+func (i *RedisTaskInspector) Queues() ([]domain_task.Queue, error) {
+	names := make([]string, 0, len(queuePriorities()))
+	for name := range queuePriorities() {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	queues := make([]domain_task.Queue, 0, len(names))
+
+	for _, name := range names {
+		info, err := i.inspector.GetQueueInfo(name)
+		if err != nil {
+			// asynq creates a queue on the first enqueued task.
+			if errors.Is(err, asynq.ErrQueueNotFound) {
+				queues = append(queues, domain_task.Queue{Name: name})
+
+				continue
+			}
+
+			return nil, err
+		}
+
+		queues = append(queues, newQueue(info))
+	}
+
+	return queues, nil
+}
+
+func (i *RedisTaskInspector) ListTasks(inp domain_task.ListTasksInput) ([]domain_task.Task, error) {
+	if err := checkQueue(inp.Queue); err != nil {
+		return nil, err
+	}
+
+	opts := []asynq.ListOption{
+		asynq.Page(inp.Page),
+		asynq.PageSize(inp.PageSize),
+	}
+
+	var (
+		infos []*asynq.TaskInfo
+		err   error
+	)
+
+	switch inp.State {
+	case domain_task.StateArchived:
+		infos, err = i.inspector.ListArchivedTasks(inp.Queue, opts...)
+	case domain_task.StateRetry:
+		infos, err = i.inspector.ListRetryTasks(inp.Queue, opts...)
+	default:
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnknownTaskState, inp.State)
+	}
+
+	if err != nil {
+		if errors.Is(err, asynq.ErrQueueNotFound) {
+			return []domain_task.Task{}, nil
+		}
+
+		return nil, err
+	}
+
+	tasks := make([]domain_task.Task, 0, len(infos))
+	for _, info := range infos {
+		tasks = append(tasks, newTask(info))
+	}
+
+	return tasks, nil
+}
+
+func (i *RedisTaskInspector) RunTask(queue, id string) error {
+	if err := checkQueue(queue); err != nil {
+		return err
+	}
+
+	return inspectorError(i.inspector.RunTask(queue, id))
+}
+
+func (i *RedisTaskInspector) DeleteTask(queue, id string) error {
+	if err := checkQueue(queue); err != nil {
+		return err
+	}
+
+	return inspectorError(i.inspector.DeleteTask(queue, id))
+}
+
+func (i *RedisTaskInspector) PauseQueue(queue string) error {
+	if err := checkQueue(queue); err != nil {
+		return err
+	}
+
+	return inspectorError(i.inspector.PauseQueue(queue))
+}
+
+func (i *RedisTaskInspector) UnpauseQueue(queue string) error {
+	if err := checkQueue(queue); err != nil {
+		return err
+	}
+
+	return inspectorError(i.inspector.UnpauseQueue(queue))
+}
+
+func checkQueue(queue string) error {
+	if _, ok := queuePriorities()[queue]; !ok {
+		return fmt.Errorf("%w: %s", domain.ErrUnknownQueue, queue)
+	}
+
+	return nil
+}
+
+// inspectorError translates the asynq lookup errors to the domain ones.
+func inspectorError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, asynq.ErrQueueNotFound), errors.Is(err, asynq.ErrTaskNotFound):
+		return domain.ErrTaskNotFound
+	default:
+		return err
+	}
+}
+
+func newQueue(info *asynq.QueueInfo) domain_task.Queue {
+	return domain_task.Queue{
+		Name:      info.Queue,
+		Size:      info.Size,
+		Pending:   info.Pending,
+		Active:    info.Active,
+		Scheduled: info.Scheduled,
+		Retry:     info.Retry,
+		Archived:  info.Archived,
+		Processed: info.Processed,
+		Failed:    info.Failed,
+		Paused:    info.Paused,
+	}
+}
+
+func newTask(info *asynq.TaskInfo) domain_task.Task {
+	return domain_task.Task{
+		ID:            info.ID,
+		Queue:         info.Queue,
+		Type:          info.Type,
+		State:         info.State.String(),
+		Payload:       redact.JSON(info.Payload),
+		MaxRetry:      info.MaxRetry,
+		Retried:       info.Retried,
+		LastError:     redact.String(info.LastErr),
+		LastFailedAt:  info.LastFailedAt,
+		NextProcessAt: info.NextProcessAt,
+	}
+}
@@ -0,0 +1,36 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/b0shka/backend/internal/domain"
+	domain_task "github.com/b0shka/backend/internal/domain/task"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTask_Redacted(t *testing.T) {
+	task := newTask(&asynq.TaskInfo{
+		ID:      "task_id",
+		Queue:   QueueCritical,
+		Type:    TaskSendVerifyEmail,
+		Payload: []byte(`{"email":"john@example.com","secret_code":"123456"}`),
+		State:   asynq.TaskStateArchived,
+		LastErr: "failed to send email to john@example.com",
+	})
+
+	require.Equal(t, domain_task.StateArchived, task.State)
+	require.JSONEq(t, `{"email":"j***@example.com","secret_code":"[REDACTED]"}`, string(task.Payload))
+	require.Equal(t, "failed to send email to j***@example.com", task.LastError)
+}
+
+func TestRedisTaskInspector_UnknownQueue(t *testing.T) {
+	inspector := &RedisTaskInspector{}
+
+	_, err := inspector.ListTasks(domain_task.NewListTasksInput("unknown", domain_task.StateArchived, 1, 30))
+	require.True(t, errors.Is(err, domain.ErrUnknownQueue))
+
+	err = inspector.PauseQueue("unknown")
+	require.True(t, errors.Is(err, domain.ErrUnknownQueue))
+}
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/b0shka/backend/internal/worker (interfaces: TaskDistributor)
+// Source: github.com/b0shka/backend/internal/worker (interfaces: TaskDistributor,TaskInspector)
 
 // Package mock_worker is a generated GoMock package.
 package mock_worker
@@ -8,6 +8,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	task "github.com/b0shka/backend/internal/domain/task"
 	worker "github.com/b0shka/backend/internal/worker"
 	gomock "github.com/golang/mock/gomock"
 	asynq "github.com/hibiken/asynq"
@@ -36,6 +37,58 @@ func (m *MockTaskDistributor) EXPECT() *MockTaskDistributorMockRecorder {
 	return m.recorder
 }
 
+// Close mocks base method.
+func (m *MockTaskDistributor) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockTaskDistributorMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockTaskDistributor)(nil).Close))
+}
+
+// DistributeTaskDeliverWebhook mocks base method.
+func (m *MockTaskDistributor) DistributeTaskDeliverWebhook(arg0 context.Context, arg1 *worker.PayloadDeliverWebhook, arg2 ...asynq.Option) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DistributeTaskDeliverWebhook", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DistributeTaskDeliverWebhook indicates an expected call of DistributeTaskDeliverWebhook.
+func (mr *MockTaskDistributorMockRecorder) DistributeTaskDeliverWebhook(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributeTaskDeliverWebhook", reflect.TypeOf((*MockTaskDistributor)(nil).DistributeTaskDeliverWebhook), varargs...)
+}
+
+// DistributeTaskPurgeExpired mocks base method.
+func (m *MockTaskDistributor) DistributeTaskPurgeExpired(arg0 context.Context, arg1 *worker.PayloadPurgeExpired, arg2 ...asynq.Option) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DistributeTaskPurgeExpired", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DistributeTaskPurgeExpired indicates an expected call of DistributeTaskPurgeExpired.
+func (mr *MockTaskDistributorMockRecorder) DistributeTaskPurgeExpired(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributeTaskPurgeExpired", reflect.TypeOf((*MockTaskDistributor)(nil).DistributeTaskPurgeExpired), varargs...)
+}
+
 // DistributeTaskSendLoginNotification mocks base method.
 func (m *MockTaskDistributor) DistributeTaskSendLoginNotification(arg0 context.Context, arg1 *worker.PayloadSendLoginNotification, arg2 ...asynq.Option) error {
 	m.ctrl.T.Helper()
@@ -73,3 +126,126 @@ func (mr *MockTaskDistributorMockRecorder) DistributeTaskSendVerifyEmail(arg0, a
 	varargs := append([]interface{}{arg0, arg1}, arg2...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributeTaskSendVerifyEmail", reflect.TypeOf((*MockTaskDistributor)(nil).DistributeTaskSendVerifyEmail), varargs...)
 }
+
+// MockTaskInspector is a mock of TaskInspector interface.
+type MockTaskInspector struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaskInspectorMockRecorder
+}
+
+// MockTaskInspectorMockRecorder is the mock recorder for MockTaskInspector.
+type MockTaskInspectorMockRecorder struct {
+	mock *MockTaskInspector
+}
+
+// NewMockTaskInspector creates a new mock instance.
+func NewMockTaskInspector(ctrl *gomock.Controller) *MockTaskInspector {
+	mock := &MockTaskInspector{ctrl: ctrl}
+	mock.recorder = &MockTaskInspectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaskInspector) EXPECT() *MockTaskInspectorMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockTaskInspector) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockTaskInspectorMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockTaskInspector)(nil).Close))
+}
+
+// DeleteTask mocks base method.
+func (m *MockTaskInspector) DeleteTask(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTask", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTask indicates an expected call of DeleteTask.
+func (mr *MockTaskInspectorMockRecorder) DeleteTask(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockTaskInspector)(nil).DeleteTask), arg0, arg1)
+}
+
+// ListTasks mocks base method.
+func (m *MockTaskInspector) ListTasks(arg0 task.ListTasksInput) ([]task.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTasks", arg0)
+	ret0, _ := ret[0].([]task.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTasks indicates an expected call of ListTasks.
+func (mr *MockTaskInspectorMockRecorder) ListTasks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasks", reflect.TypeOf((*MockTaskInspector)(nil).ListTasks), arg0)
+}
+
+// PauseQueue mocks base method.
+func (m *MockTaskInspector) PauseQueue(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseQueue", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseQueue indicates an expected call of PauseQueue.
+func (mr *MockTaskInspectorMockRecorder) PauseQueue(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseQueue", reflect.TypeOf((*MockTaskInspector)(nil).PauseQueue), arg0)
+}
+
+// Queues mocks base method.
+func (m *MockTaskInspector) Queues() ([]task.Queue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Queues")
+	ret0, _ := ret[0].([]task.Queue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Queues indicates an expected call of Queues.
+func (mr *MockTaskInspectorMockRecorder) Queues() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Queues", reflect.TypeOf((*MockTaskInspector)(nil).Queues))
+}
+
+// RunTask mocks base method.
+func (m *MockTaskInspector) RunTask(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunTask", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunTask indicates an expected call of RunTask.
+func (mr *MockTaskInspectorMockRecorder) RunTask(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunTask", reflect.TypeOf((*MockTaskInspector)(nil).RunTask), arg0, arg1)
+}
+
+// UnpauseQueue mocks base method.
+func (m *MockTaskInspector) UnpauseQueue(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpauseQueue", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnpauseQueue indicates an expected call of UnpauseQueue.
+func (mr *MockTaskInspectorMockRecorder) UnpauseQueue(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpauseQueue", reflect.TypeOf((*MockTaskInspector)(nil).UnpauseQueue), arg0)
+}
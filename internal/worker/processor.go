@@ -3,13 +3,25 @@ package worker
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"os"
+	"time"
 
 	"github.com/b0shka/backend/internal/config"
+	"github.com/b0shka/backend/internal/metrics"
 	repository "github.com/b0shka/backend/internal/repository/postgresql"
+	sqlc "github.com/b0shka/backend/internal/repository/postgresql/sqlc"
 	"github.com/b0shka/backend/pkg/email"
 	"github.com/b0shka/backend/pkg/hash"
+	"github.com/b0shka/backend/pkg/i18n"
 	"github.com/b0shka/backend/pkg/identity"
 	"github.com/b0shka/backend/pkg/logger"
+	"github.com/b0shka/backend/pkg/requestid"
+	"github.com/b0shka/backend/pkg/webhook"
 	"github.com/hibiken/asynq"
 )
 
@@ -20,18 +32,231 @@ const (
 
 type TaskProcessor interface {
 	Start() error
+	Shutdown(ctx context.Context) error
 	ProcessTaskSendVerifyEmail(ctx context.Context, task *asynq.Task) error
 	ProcessTaskSendLoginNotification(ctx context.Context, task *asynq.Task) error
+	ProcessTaskDeliverWebhook(ctx context.Context, task *asynq.Task) error
+	ProcessTaskPurgeExpired(ctx context.Context, task *asynq.Task) error
+}
+
+// queuePriorities returns the queues served by the processors with their
+// relative priority.
+func queuePriorities() map[string]int {
+	return map[string]int{
+		QueueCritical: 10,
+		QueueDefault:  5,
+	}
+}
+
+// taskHandlers implements the task handlers shared by every backend.
+type taskHandlers struct {
+	repos         *repository.Repositories
+	hasher        hash.Hasher
+	idGenerator   identity.Generator
+	emailService  *email.EmailService
+	i18n          *i18n.Bundle
+	emailConfig   config.EmailConfig
+	authConfig    config.AuthConfig
+	webhookConfig config.WebhooksConfig
+	cleanupConfig config.CleanupConfig
+	httpClient    *http.Client
+}
+
+func newTaskHandlers(
+	repos *repository.Repositories,
+	hasher hash.Hasher,
+	idGenerator identity.Generator,
+	emailService *email.EmailService,
+	bundle *i18n.Bundle,
+	emailConfig config.EmailConfig,
+	authConfig config.AuthConfig,
+	webhookConfig config.WebhooksConfig,
+	cleanupConfig config.CleanupConfig,
+) *taskHandlers {
+	return &taskHandlers{
+		repos:         repos,
+		hasher:        hasher,
+		idGenerator:   idGenerator,
+		emailService:  emailService,
+		i18n:          bundle,
+		emailConfig:   emailConfig,
+		authConfig:    authConfig,
+		webhookConfig: webhookConfig,
+		cleanupConfig: cleanupConfig,
+		httpClient:    webhook.NewClient(webhookConfig.Timeout),
+	}
+}
+
+func (processor *taskHandlers) serveMux() *asynq.ServeMux {
+	mux := asynq.NewServeMux()
+
+	mux.Use(loggingMiddleware, tracingMiddleware, metricsMiddleware)
+	mux.HandleFunc(TaskSendVerifyEmail, processor.ProcessTaskSendVerifyEmail)
+	mux.HandleFunc(TaskSendLoginNotification, processor.ProcessTaskSendLoginNotification)
+	mux.HandleFunc(TaskDeliverWebhook, processor.ProcessTaskDeliverWebhook)
+	mux.HandleFunc(TaskPurgeExpired, processor.ProcessTaskPurgeExpired)
+
+	return mux
+}
+
+// retryDelay backs webhook deliveries off exponentially and keeps the asynq
+// default for every other task.
+func (processor *taskHandlers) retryDelay(n int, err error, task *asynq.Task) time.Duration {
+	if task.Type() != TaskDeliverWebhook {
+		return asynq.DefaultRetryDelayFunc(n, err, task)
+	}
+
+	delay := time.Duration(float64(processor.webhookConfig.BackoffBase) * math.Pow(2, float64(n)))
+	if delay <= 0 || delay > processor.webhookConfig.BackoffMax {
+		return processor.webhookConfig.BackoffMax
+	}
+
+	return delay
+}
+
+// loggingMiddleware adds the task to the context logger of the asynq
+// processor, the rabbitmq processor adds it when it receives the delivery.
+// The request ID and the locale of the request that enqueued the task are
+// restored for both.
+func loggingMiddleware(next asynq.Handler) asynq.Handler {
+	return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		if id, ok := asynq.GetTaskID(ctx); ok {
+			ctx = logger.With(ctx, logger.KeyTaskID, id, logger.KeyTaskType, task.Type())
+		}
+
+		var taskContext TaskContext
+		if err := json.Unmarshal(task.Payload(), &taskContext); err == nil {
+			if taskContext.RequestID != "" {
+				ctx = requestid.WithContext(ctx, taskContext.RequestID)
+				ctx = logger.With(ctx, logger.KeyRequestID, taskContext.RequestID)
+			}
+
+			if taskContext.Locale != "" {
+				ctx = i18n.WithLocale(ctx, taskContext.Locale)
+			}
+		}
+
+		return next.ProcessTask(ctx, task)
+	})
+}
+
+// metricsMiddleware records the outcome and the duration of every task
+// attempt, it is shared by both backends through the serve mux.
+func metricsMiddleware(next asynq.Handler) asynq.Handler {
+	return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		start := time.Now()
+		err := next.ProcessTask(ctx, task)
+
+		metrics.ObserveTask(task.Type(), err, time.Since(start))
+
+		return err
+	})
+}
+
+// sendEmail sends the email rendered from the template in the locale of the
+// recipient and counts it by the template name. The subject and the texts of
+// the template come from the "email.<template>" messages of the catalogs.
+func (processor *taskHandlers) sendEmail(
+	ctx context.Context,
+	toEmail, template, templateFile string,
+	contentData any,
+) error {
+	locale := processor.recipientLocale(ctx, toEmail)
+	subject := processor.i18n.Translate(locale, "email."+template+".subject")
+
+	err := processor.emailService.SendEmail(
+		ctx,
+		toEmail,
+		templateFile,
+		subject,
+		contentData,
+		processor.i18n.Funcs(locale),
+	)
+	metrics.ObserveEmail(template, err)
+
+	return err
+}
+
+// recipientLocale prefers the locale saved by the recipient to the locale
+// negotiated for the request that enqueued the task, the recipients without
+// an account yet get the latter.
+func (processor *taskHandlers) recipientLocale(ctx context.Context, toEmail string) string {
+	user, err := processor.repos.Users.GetByEmail(ctx, toEmail)
+	if err != nil && !errors.Is(err, sqlc.ErrRecordNotFound) {
+		logger.FromContext(ctx).Warn("failed to get recipient locale", logger.Err(err))
+	}
+
+	return processor.i18n.Match(user.Locale, i18n.LocaleFromContext(ctx))
+}
+
+type taskMetadataKey struct{}
+
+type taskMetadata struct {
+	retryCount int
+	maxRetry   int
+}
+
+func withTaskMetadata(ctx context.Context, metadata taskMetadata) context.Context {
+	return context.WithValue(ctx, taskMetadataKey{}, metadata)
+}
+
+// isLastAttempt reports whether the task will not be retried after a failure.
+// It understands the context of both the asynq and the rabbitmq processors.
+func isLastAttempt(ctx context.Context) bool {
+	if metadata, ok := ctx.Value(taskMetadataKey{}).(taskMetadata); ok {
+		return metadata.retryCount >= metadata.maxRetry
+	}
+
+	retryCount, ok := asynq.GetRetryCount(ctx)
+	if !ok {
+		return false
+	}
+
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+
+	return retryCount >= maxRetry
+}
+
+func handleTaskError(ctx context.Context, task *asynq.Task, err error) {
+	var data map[string]interface{}
+	verr := json.Unmarshal(task.Payload(), &data)
+	if verr != nil {
+		logger.FromContext(ctx).Error("failed to decode payload", logger.KeyTaskType, task.Type(), logger.Err(verr))
+
+		return
+	}
+
+	logger.FromContext(ctx).Error("process task failed",
+		logger.KeyTaskType, task.Type(), "payload", data, logger.Err(err))
+}
+
+// asynqLogger writes the logs of the asynq server with the default logger.
+type asynqLogger struct{}
+
+func (asynqLogger) Debug(args ...any) {
+	slog.Debug(fmt.Sprint(args...))
+}
+
+func (asynqLogger) Info(args ...any) {
+	slog.Info(fmt.Sprint(args...))
+}
+
+func (asynqLogger) Warn(args ...any) {
+	slog.Warn(fmt.Sprint(args...))
+}
+
+func (asynqLogger) Error(args ...any) {
+	slog.Error(fmt.Sprint(args...))
+}
+
+func (asynqLogger) Fatal(args ...any) {
+	slog.Error(fmt.Sprint(args...))
+	os.Exit(1)
 }
 
 type RedisTaskProcessor struct {
-	server       *asynq.Server
-	repos        *repository.Repositories
-	hasher       hash.Hasher
-	idGenerator  identity.Generator
-	emailService *email.EmailService
-	emailConfig  config.EmailConfig
-	authConfig   config.AuthConfig
+	*taskHandlers
+	server *asynq.Server
 }
 
 func NewRedisTaskProcessor(
@@ -40,47 +265,61 @@ func NewRedisTaskProcessor(
 	hasher hash.Hasher,
 	idGenerator identity.Generator,
 	emailService *email.EmailService,
+	bundle *i18n.Bundle,
+	workerConfig config.WorkerConfig,
 	emailConfig config.EmailConfig,
 	authConfig config.AuthConfig,
+	webhookConfig config.WebhooksConfig,
+	cleanupConfig config.CleanupConfig,
 ) TaskProcessor {
+	handlers := newTaskHandlers(
+		repos,
+		hasher,
+		idGenerator,
+		emailService,
+		bundle,
+		emailConfig,
+		authConfig,
+		webhookConfig,
+		cleanupConfig,
+	)
+
 	server := asynq.NewServer(
 		redisOpt,
 		asynq.Config{
-			Queues: map[string]int{
-				QueueCritical: 10,
-				QueueDefault:  5,
-			},
-			ErrorHandler: asynq.ErrorHandlerFunc(func(ctx context.Context, task *asynq.Task, err error) {
-				var data map[string]interface{}
-				verr := json.Unmarshal(task.Payload(), &data)
-				if verr != nil {
-					logger.Errorf("Error decode payload: %s", verr.Error())
-
-					return
-				}
-
-				logger.Errorf("process task failed: type - %s, payload - %v, err - %s", task.Type(), data, err.Error())
-			}),
-			// Logger: logger,
+			Queues:          queuePriorities(),
+			RetryDelayFunc:  handlers.retryDelay,
+			ErrorHandler:    asynq.ErrorHandlerFunc(handleTaskError),
+			Logger:          asynqLogger{},
+			ShutdownTimeout: workerConfig.ShutdownTimeout,
 		},
 	)
 
 	return &RedisTaskProcessor{
+		taskHandlers: handlers,
 		server:       server,
-		repos:        repos,
-		hasher:       hasher,
-		idGenerator:  idGenerator,
-		emailService: emailService,
-		emailConfig:  emailConfig,
-		authConfig:   authConfig,
 	}
 }
 
 func (processor *RedisTaskProcessor) Start() error {
-	mux := asynq.NewServeMux()
+	return processor.server.Start(processor.serveMux())
+}
 
-	mux.HandleFunc(TaskSendVerifyEmail, processor.ProcessTaskSendVerifyEmail)
-	mux.HandleFunc(TaskSendLoginNotification, processor.ProcessTaskSendLoginNotification)
+// Shutdown stops fetching the tasks and waits for the tasks in flight for the
+// shutdown timeout of the worker, asynq pushes the unfinished ones back to
+// their queue then.
+func (processor *RedisTaskProcessor) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
 
-	return processor.server.Start(mux)
+	go func() {
+		processor.server.Shutdown()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
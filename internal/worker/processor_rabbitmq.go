@@ -0,0 +1,298 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/b0shka/backend/internal/config"
+	repository "github.com/b0shka/backend/internal/repository/postgresql"
+	"github.com/b0shka/backend/pkg/broker/rabbitmq"
+	"github.com/b0shka/backend/pkg/email"
+	"github.com/b0shka/backend/pkg/hash"
+	"github.com/b0shka/backend/pkg/i18n"
+	"github.com/b0shka/backend/pkg/identity"
+	"github.com/b0shka/backend/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/streadway/amqp"
+)
+
+const (
+	resubscribeDelay = 5 * time.Second
+	republishTimeout = 10 * time.Second
+)
+
+type RabbitMQTaskProcessor struct {
+	*taskHandlers
+	client    *rabbitmq.Client
+	publisher *rabbitmq.Publisher
+	topology  rabbitMQTopology
+
+	consumers    sync.WaitGroup
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+}
+
+func NewRabbitMQTaskProcessor(
+	client *rabbitmq.Client,
+	exchange string,
+	repos *repository.Repositories,
+	hasher hash.Hasher,
+	idGenerator identity.Generator,
+	emailService *email.EmailService,
+	bundle *i18n.Bundle,
+	emailConfig config.EmailConfig,
+	authConfig config.AuthConfig,
+	webhookConfig config.WebhooksConfig,
+	cleanupConfig config.CleanupConfig,
+) TaskProcessor {
+	topology := newRabbitMQTopology(exchange)
+
+	return &RabbitMQTaskProcessor{
+		taskHandlers: newTaskHandlers(
+			repos,
+			hasher,
+			idGenerator,
+			emailService,
+			bundle,
+			emailConfig,
+			authConfig,
+			webhookConfig,
+			cleanupConfig,
+		),
+		client:    client,
+		publisher: rabbitmq.NewPublisher(client, topology.declare),
+		topology:  topology,
+		shutdown:  make(chan struct{}),
+	}
+}
+
+func (processor *RabbitMQTaskProcessor) Start() error {
+	// declares the topology up front so that a misconfigured broker is
+	// reported on start instead of in the consumer loop.
+	if err := processor.publisher.Declare(processor.topology.declare); err != nil {
+		return err
+	}
+
+	mux := processor.serveMux()
+
+	for queue, priority := range queuePriorities() {
+		processor.consumers.Add(1)
+
+		go func(queue string, priority int) {
+			defer processor.consumers.Done()
+
+			processor.consume(queue, priority, mux)
+		}(queue, priority)
+	}
+
+	return nil
+}
+
+// Shutdown cancels the consumers and waits until the tasks already delivered
+// are handled, the tasks left unacked when the context is done are requeued
+// by the broker once the connection is closed.
+func (processor *RabbitMQTaskProcessor) Shutdown(ctx context.Context) error {
+	processor.shutdownOnce.Do(func() {
+		close(processor.shutdown)
+	})
+
+	stopped := make(chan struct{})
+
+	go func() {
+		processor.consumers.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return processor.publisher.Close()
+}
+
+// consume serves the queue with as many concurrent handlers as the queue
+// priority and resubscribes whenever the channel is closed, until the
+// processor shuts down.
+func (processor *RabbitMQTaskProcessor) consume(queue string, concurrency int, handler asynq.Handler) {
+	for {
+		tag := processor.topology.queueName(queue) + "." + uuid.NewString()
+
+		channel, deliveries, err := processor.subscribe(queue, tag, concurrency)
+		if err != nil {
+			slog.Error("failed to subscribe to queue", "queue", queue, logger.Err(err))
+
+			if !processor.wait(resubscribeDelay) {
+				return
+			}
+
+			continue
+		}
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				for delivery := range deliveries {
+					processor.handle(queue, delivery, handler)
+				}
+			}()
+		}
+
+		handled := make(chan struct{})
+
+		go func() {
+			wg.Wait()
+			close(handled)
+		}()
+
+		select {
+		case <-handled:
+		case <-processor.shutdown:
+			// the broker stops the deliveries on cancel, the deliveries
+			// already received are handled before the channel is closed.
+			if err := channel.Cancel(tag, false); err != nil {
+				slog.Error("failed to cancel consumer", "queue", queue, logger.Err(err))
+			}
+
+			<-handled
+			channel.Close()
+
+			return
+		}
+
+		channel.Close()
+
+		slog.Error("consumer of queue stopped, going to resubscribe", "queue", queue)
+
+		if !processor.wait(resubscribeDelay) {
+			return
+		}
+	}
+}
+
+// wait sleeps for the delay and reports false when the processor shuts down
+// meanwhile.
+func (processor *RabbitMQTaskProcessor) wait(delay time.Duration) bool {
+	select {
+	case <-time.After(delay):
+		return true
+	case <-processor.shutdown:
+		return false
+	}
+}
+
+func (processor *RabbitMQTaskProcessor) subscribe(
+	queue, tag string,
+	prefetch int,
+) (*amqp.Channel, <-chan amqp.Delivery, error) {
+	channel, err := processor.client.Channel()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := channel.Qos(prefetch, 0, false); err != nil {
+		channel.Close()
+
+		return nil, nil, err
+	}
+
+	deliveries, err := channel.Consume(processor.topology.queueName(queue), tag, false, false, false, false, nil)
+	if err != nil {
+		channel.Close()
+
+		return nil, nil, err
+	}
+
+	return channel, deliveries, nil
+}
+
+func (processor *RabbitMQTaskProcessor) handle(queue string, delivery amqp.Delivery, handler asynq.Handler) {
+	timeout := time.Duration(headerInt(delivery.Headers, headerTimeout, defaultTimeout.Milliseconds())) * time.Millisecond
+	task := asynq.NewTask(delivery.Type, delivery.Body)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ctx = withTaskMetadata(ctx, taskMetadata{
+		retryCount: int(headerInt(delivery.Headers, headerRetried, 0)),
+		maxRetry:   int(headerInt(delivery.Headers, headerMaxRetry, defaultMaxRetry)),
+	})
+	ctx = logger.With(ctx, logger.KeyTaskID, delivery.MessageId, logger.KeyTaskType, task.Type())
+
+	err := handler.ProcessTask(ctx, task)
+	if err == nil {
+		if err := delivery.Ack(false); err != nil {
+			logger.FromContext(ctx).Error("failed to ack task", logger.Err(err))
+		}
+
+		return
+	}
+
+	handleTaskError(ctx, task, err)
+
+	if err := processor.retryOrArchive(queue, delivery, task, err); err != nil {
+		logger.FromContext(ctx).Error("failed to reschedule task", logger.Err(err))
+
+		if err := delivery.Nack(false, true); err != nil {
+			logger.FromContext(ctx).Error("failed to nack task", logger.Err(err))
+		}
+
+		return
+	}
+
+	if err := delivery.Ack(false); err != nil {
+		logger.FromContext(ctx).Error("failed to ack task", logger.Err(err))
+	}
+}
+
+// retryOrArchive schedules the next attempt of a failed task after the same
+// delay the redis backend would use, or archives the task once its retries
+// are exhausted.
+func (processor *RabbitMQTaskProcessor) retryOrArchive(
+	queue string,
+	delivery amqp.Delivery,
+	task *asynq.Task,
+	taskErr error,
+) error {
+	retried := int(headerInt(delivery.Headers, headerRetried, 0))
+	maxRetry := int(headerInt(delivery.Headers, headerMaxRetry, defaultMaxRetry))
+
+	headers := amqp.Table{}
+	for key, value := range delivery.Headers {
+		headers[key] = value
+	}
+
+	headers[headerRetried] = int64(retried + 1)
+	headers[headerLastError] = taskErr.Error()
+
+	msg := amqp.Publishing{
+		ContentType:  delivery.ContentType,
+		DeliveryMode: amqp.Persistent,
+		MessageId:    delivery.MessageId,
+		Type:         delivery.Type,
+		Timestamp:    delivery.Timestamp,
+		Body:         delivery.Body,
+		Headers:      headers,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), republishTimeout)
+	defer cancel()
+
+	if retried >= maxRetry || errors.Is(taskErr, asynq.SkipRetry) {
+		return processor.publisher.Publish(ctx, "", processor.topology.archiveQueueName(queue), msg)
+	}
+
+	delay := processor.retryDelay(retried, taskErr, task)
+
+	return processor.topology.publish(ctx, processor.publisher, queue, delay, msg)
+}
@@ -0,0 +1,119 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/b0shka/backend/internal/config"
+	domain_user "github.com/b0shka/backend/internal/domain/user"
+	repository "github.com/b0shka/backend/internal/repository/postgresql"
+	mock_repository "github.com/b0shka/backend/internal/repository/postgresql/mocks"
+	sqlc "github.com/b0shka/backend/internal/repository/postgresql/sqlc"
+	"github.com/b0shka/backend/pkg/broker/rabbitmq"
+	"github.com/b0shka/backend/pkg/i18n"
+	"github.com/b0shka/backend/pkg/logger"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskLogsRedacted(t *testing.T) {
+	var buf bytes.Buffer
+
+	log, err := logger.New(&buf, config.LoggerConfig{Level: "debug"}, config.EnvProd)
+	require.NoError(t, err)
+
+	ctx := logger.WithContext(context.Background(), log)
+
+	verifyPayload, err := marshalPayload(ctx, &PayloadSendVerifyEmail{Email: "john@example.com", SecretCode: "123456"})
+	require.NoError(t, err)
+
+	loginPayload, err := marshalPayload(ctx, &PayloadSendLoginNotification{
+		Email:     "john@example.com",
+		UserAgent: "Mozilla/5.0",
+		ClientIP:  "203.0.113.42",
+		Time:      time.Now().Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+
+	handleTaskError(ctx, asynq.NewTask(TaskSendVerifyEmail, verifyPayload),
+		fmt.Errorf("failed to send verify email to %s: %w", "john@example.com", errTest))
+	handleTaskError(ctx, asynq.NewTask(TaskSendLoginNotification, loginPayload), errTest)
+	log.Info("enqueued task", "payload", &PayloadSendVerifyEmail{Email: "john@example.com", SecretCode: "123456"})
+
+	require.Contains(t, buf.String(), "j***@example.com")
+
+	for _, secret := range []string{"john@example.com", "123456", "203.0.113.42"} {
+		require.NotContains(t, buf.String(), secret)
+	}
+}
+
+func TestRecipientLocale(t *testing.T) {
+	bundle, err := i18n.NewBundle("en", map[string]i18n.Catalog{"en": {}, "ru": {}})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		userLocale    string
+		userErr       error
+		requestLocale string
+		locale        string
+	}{
+		"SavedLocale":       {userLocale: "ru", requestLocale: "en", locale: "ru"},
+		"RequestLocale":     {requestLocale: "ru", locale: "ru"},
+		"UnknownRecipient":  {userErr: sqlc.ErrRecordNotFound, requestLocale: "ru", locale: "ru"},
+		"RepositoryFailure": {userErr: fmt.Errorf("connection refused"), requestLocale: "ru", locale: "ru"},
+		"Default":           {locale: "en"},
+	}
+
+	for name, testCase := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			defer mockCtl.Finish()
+
+			usersRepo := mock_repository.NewMockUsers(mockCtl)
+			usersRepo.EXPECT().
+				GetByEmail(gomock.Any(), "email@ya.ru").
+				Return(domain_user.User{Locale: testCase.userLocale}, testCase.userErr)
+
+			processor := &taskHandlers{
+				repos: &repository.Repositories{Users: usersRepo},
+				i18n:  bundle,
+			}
+
+			ctx := context.Background()
+			if testCase.requestLocale != "" {
+				ctx = i18n.WithLocale(ctx, testCase.requestLocale)
+			}
+
+			require.Equal(t, testCase.locale, processor.recipientLocale(ctx, "email@ya.ru"))
+		})
+	}
+}
+
+func TestRabbitMQTaskProcessor_Shutdown(t *testing.T) {
+	processor := &RabbitMQTaskProcessor{
+		publisher: rabbitmq.NewPublisher(nil, nil),
+		shutdown:  make(chan struct{}),
+	}
+
+	// a consumer that handles a task until the processor shuts down.
+	processor.consumers.Add(1)
+
+	go func() {
+		defer processor.consumers.Done()
+
+		<-processor.shutdown
+		time.Sleep(time.Millisecond * 50)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+
+	require.ErrorIs(t, processor.Shutdown(ctx), context.DeadlineExceeded)
+	require.False(t, processor.wait(time.Minute))
+
+	require.NoError(t, processor.Shutdown(context.Background()))
+}
@@ -0,0 +1,172 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/b0shka/backend/internal/domain"
+	"github.com/b0shka/backend/pkg/broker/rabbitmq"
+	"github.com/hibiken/asynq"
+	"github.com/streadway/amqp"
+)
+
+const (
+	headerMaxRetry  = "x-max-retry"
+	headerRetried   = "x-retried"
+	headerTimeout   = "x-timeout"
+	headerLastError = "x-last-error"
+
+	// defaults mirror the ones used by asynq for the redis backend.
+	defaultMaxRetry = 25
+	defaultTimeout  = 30 * time.Minute
+
+	minDelay = time.Second
+)
+
+// taskOptions is the subset of asynq options supported by the rabbitmq backend,
+// the task is not distributed when any other option is given. The task ID is
+// the message ID, the messages are not deduplicated by it.
+type taskOptions struct {
+	queue     string
+	maxRetry  int
+	timeout   time.Duration
+	processIn time.Duration
+	taskID    string
+}
+
+func composeTaskOptions(opts ...asynq.Option) (taskOptions, error) {
+	res := taskOptions{
+		queue:    QueueDefault,
+		maxRetry: defaultMaxRetry,
+		timeout:  defaultTimeout,
+	}
+
+	for _, opt := range opts {
+		switch opt.Type() {
+		case asynq.QueueOpt:
+			res.queue, _ = opt.Value().(string)
+		case asynq.TaskIDOpt:
+			res.taskID, _ = opt.Value().(string)
+		case asynq.MaxRetryOpt:
+			res.maxRetry, _ = opt.Value().(int)
+		case asynq.TimeoutOpt:
+			res.timeout, _ = opt.Value().(time.Duration)
+		case asynq.ProcessInOpt:
+			res.processIn, _ = opt.Value().(time.Duration)
+		case asynq.ProcessAtOpt:
+			if processAt, ok := opt.Value().(time.Time); ok {
+				res.processIn = time.Until(processAt)
+			}
+		case asynq.DeadlineOpt, asynq.UniqueOpt, asynq.RetentionOpt, asynq.GroupOpt:
+			return taskOptions{}, fmt.Errorf("%w: %s", domain.ErrUnsupportedTaskOption, opt.String())
+		}
+	}
+
+	if _, ok := queuePriorities()[res.queue]; !ok {
+		return taskOptions{}, fmt.Errorf("%w: %s", domain.ErrUnknownQueue, res.queue)
+	}
+
+	return res, nil
+}
+
+// rabbitMQTopology describes the exchange and queues used by the rabbitmq
+// backend. Every asynq queue maps to a durable queue bound to a direct
+// exchange, failed tasks wait for the next attempt in TTL queues that
+// dead-letter back to the exchange, and exhausted tasks are moved to an
+// archive queue.
+type rabbitMQTopology struct {
+	exchange string
+}
+
+func newRabbitMQTopology(exchange string) rabbitMQTopology {
+	return rabbitMQTopology{
+		exchange: exchange,
+	}
+}
+
+func (t rabbitMQTopology) queueName(queue string) string {
+	return fmt.Sprintf("%s.%s", t.exchange, queue)
+}
+
+func (t rabbitMQTopology) archiveQueueName(queue string) string {
+	return fmt.Sprintf("%s.%s.archived", t.exchange, queue)
+}
+
+func (t rabbitMQTopology) delayQueueName(queue string, delay time.Duration) string {
+	return fmt.Sprintf("%s.%s.delay.%d", t.exchange, queue, delay.Milliseconds())
+}
+
+func (t rabbitMQTopology) declare(channel *amqp.Channel) error {
+	if err := channel.ExchangeDeclare(t.exchange, amqp.ExchangeDirect, true, false, false, false, nil); err != nil {
+		return err
+	}
+
+	for queue := range queuePriorities() {
+		if _, err := channel.QueueDeclare(t.queueName(queue), true, false, false, false, nil); err != nil {
+			return err
+		}
+
+		if err := channel.QueueBind(t.queueName(queue), queue, t.exchange, false, nil); err != nil {
+			return err
+		}
+
+		if _, err := channel.QueueDeclare(t.archiveQueueName(queue), true, false, false, false, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// publish routes the message to the queue, going through a delay queue first
+// when the message must not be processed before the delay has passed.
+func (t rabbitMQTopology) publish(
+	ctx context.Context,
+	publisher *rabbitmq.Publisher,
+	queue string,
+	delay time.Duration,
+	msg amqp.Publishing,
+) error {
+	if delay <= 0 {
+		return publisher.Publish(ctx, t.exchange, queue, msg)
+	}
+
+	// delays are rounded to whole seconds to limit the number of delay queues
+	delay = delay.Round(time.Second)
+	if delay < minDelay {
+		delay = minDelay
+	}
+
+	name := t.delayQueueName(queue, delay)
+
+	err := publisher.Declare(func(channel *amqp.Channel) error {
+		_, err := channel.QueueDeclare(name, true, false, false, false, amqp.Table{
+			"x-message-ttl":             delay.Milliseconds(),
+			"x-dead-letter-exchange":    t.exchange,
+			"x-dead-letter-routing-key": queue,
+			// unused delay queues are removed by the broker
+			"x-expires": (2*delay + time.Minute).Milliseconds(),
+		})
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return publisher.Publish(ctx, "", name, msg)
+}
+
+func headerInt(headers amqp.Table, key string, fallback int64) int64 {
+	switch value := headers[key].(type) {
+	case int64:
+		return value
+	case int32:
+		return int64(value)
+	case int:
+		return int64(value)
+	default:
+		return fallback
+	}
+}
@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/b0shka/backend/internal/domain"
+	"github.com/hibiken/asynq"
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComposeTaskOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []asynq.Option
+		want    taskOptions
+		wantErr error
+	}{
+		{
+			name: "defaults",
+			want: taskOptions{
+				queue:    QueueDefault,
+				maxRetry: defaultMaxRetry,
+				timeout:  defaultTimeout,
+			},
+		},
+		{
+			name: "send verify email options",
+			opts: []asynq.Option{
+				asynq.MaxRetry(10),
+				asynq.ProcessIn(time.Second),
+				asynq.Queue(QueueCritical),
+				asynq.TaskID("task_id"),
+				asynq.Timeout(time.Minute),
+			},
+			want: taskOptions{
+				queue:     QueueCritical,
+				maxRetry:  10,
+				timeout:   time.Minute,
+				processIn: time.Second,
+				taskID:    "task_id",
+			},
+		},
+		{
+			name:    "unknown queue",
+			opts:    []asynq.Option{asynq.Queue("low")},
+			wantErr: domain.ErrUnknownQueue,
+		},
+		{
+			name:    "unique",
+			opts:    []asynq.Option{asynq.Unique(time.Minute)},
+			wantErr: domain.ErrUnsupportedTaskOption,
+		},
+		{
+			name:    "deadline",
+			opts:    []asynq.Option{asynq.Deadline(time.Now().Add(time.Minute))},
+			wantErr: domain.ErrUnsupportedTaskOption,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := composeTaskOptions(testCase.opts...)
+			if testCase.wantErr != nil {
+				require.True(t, errors.Is(err, testCase.wantErr))
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testCase.want, got)
+		})
+	}
+}
+
+func TestHeaderInt(t *testing.T) {
+	headers := amqp.Table{
+		headerRetried:  int32(3),
+		headerMaxRetry: int64(10),
+		headerTimeout:  "invalid",
+	}
+
+	require.Equal(t, int64(3), headerInt(headers, headerRetried, 0))
+	require.Equal(t, int64(10), headerInt(headers, headerMaxRetry, 0))
+	require.Equal(t, int64(5), headerInt(headers, headerTimeout, 5))
+	require.Equal(t, int64(7), headerInt(headers, headerLastError, 7))
+}
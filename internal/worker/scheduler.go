@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/b0shka/backend/pkg/logger"
+	"github.com/hibiken/asynq"
+	"github.com/robfig/cron/v3"
+)
+
+const schedulerEnqueueTimeout = 10 * time.Second
+
+// EnqueueFunc hands a periodic task over to the task distributor.
+type EnqueueFunc func(ctx context.Context, opts ...asynq.Option) error
+
+// Scheduler enqueues periodic tasks through the task distributor, so the jobs
+// are processed by whichever backend the worker runs on. Every tick gets a
+// task id derived from the scheduled time, which lets the redis backend drop
+// the duplicates enqueued by other instances of the service.
+type Scheduler struct {
+	cron *cron.Cron
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		cron: cron.New(cron.WithLocation(time.UTC)),
+	}
+}
+
+// Register schedules the task with a standard cron spec. The options are
+// passed to every enqueued task.
+func (s *Scheduler) Register(spec, taskType string, enqueue EnqueueFunc, opts ...asynq.Option) error {
+	var entryID cron.EntryID
+
+	entryID, err := s.cron.AddFunc(spec, func() {
+		tick := s.cron.Entry(entryID).Prev
+
+		taskOpts := make([]asynq.Option, 0, len(opts)+1)
+		taskOpts = append(taskOpts, opts...)
+		taskOpts = append(taskOpts, asynq.TaskID(fmt.Sprintf("%s:%d", taskType, tick.Unix())))
+
+		ctx, cancel := context.WithTimeout(context.Background(), schedulerEnqueueTimeout)
+		defer cancel()
+
+		if err := enqueue(ctx, taskOpts...); err != nil {
+			if errors.Is(err, asynq.ErrTaskIDConflict) {
+				logger.FromContext(ctx).Info("periodic task is already enqueued", logger.KeyTaskType, taskType, "tick", tick)
+
+				return
+			}
+
+			logger.FromContext(ctx).Error("failed to enqueue periodic task", logger.KeyTaskType, taskType, logger.Err(err))
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("invalid schedule of task %s: %w", taskType, err)
+	}
+
+	slog.Info("registered periodic task", logger.KeyTaskType, taskType, "schedule", spec)
+
+	return nil
+}
+
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// Shutdown stops the scheduler and waits for the running enqueues.
+func (s *Scheduler) Shutdown() {
+	<-s.cron.Stop().Done()
+}
@@ -0,0 +1,166 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/b0shka/backend/internal/domain"
+	domain_webhook "github.com/b0shka/backend/internal/domain/webhook"
+	repository "github.com/b0shka/backend/internal/repository/postgresql"
+	"github.com/b0shka/backend/pkg/logger"
+	"github.com/b0shka/backend/pkg/webhook"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const TaskDeliverWebhook = "task:deliver_webhook"
+
+const (
+	HeaderWebhookID        = "X-Webhook-ID"
+	HeaderWebhookEvent     = "X-Webhook-Event"
+	HeaderWebhookTimestamp = "X-Webhook-Timestamp"
+	HeaderWebhookSignature = "X-Webhook-Signature"
+
+	webhookUserAgent = "backend-webhooks/1.0"
+)
+
+type PayloadDeliverWebhook struct {
+	TaskContext
+	DeliveryID uuid.UUID `json:"delivery_id"`
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskDeliverWebhook(
+	ctx context.Context,
+	payload *PayloadDeliverWebhook,
+	opts ...asynq.Option,
+) error {
+	return distributor.enqueue(ctx, TaskDeliverWebhook, payload, opts...)
+}
+
+func (distributor *RabbitMQTaskDistributor) DistributeTaskDeliverWebhook(
+	ctx context.Context,
+	payload *PayloadDeliverWebhook,
+	opts ...asynq.Option,
+) error {
+	return distributor.enqueue(ctx, TaskDeliverWebhook, payload, opts...)
+}
+
+func (processor *taskHandlers) ProcessTaskDeliverWebhook(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadDeliverWebhook
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	delivery, err := processor.repos.WebhookDeliveries.Get(ctx, payload.DeliveryID)
+	if err != nil {
+		return err
+	}
+
+	subscription, err := processor.repos.WebhookSubscriptions.Get(ctx, delivery.SubscriptionID)
+	if err != nil {
+		return err
+	}
+
+	if !subscription.IsActive {
+		logger.FromContext(ctx).Info("skipped webhook delivery of disabled subscription",
+			"delivery_id", delivery.ID, "subscription_id", subscription.ID)
+
+		return processor.repos.WebhookDeliveries.UpdateStatus(ctx, delivery.ID, domain_webhook.DeliveryStatusFailed)
+	}
+
+	start := time.Now()
+	responseCode, sendErr := processor.sendWebhook(ctx, subscription, delivery)
+
+	attemptParams := repository.CreateWebhookAttemptParams{
+		ID:           processor.idGenerator.GenerateUUID(),
+		DeliveryID:   delivery.ID,
+		ResponseCode: responseCode,
+		Duration:     time.Since(start),
+	}
+	if sendErr != nil {
+		attemptParams.Error = sendErr.Error()
+	}
+
+	if _, err := processor.repos.WebhookDeliveries.CreateAttempt(ctx, attemptParams); err != nil {
+		return err
+	}
+
+	if sendErr == nil {
+		if err := processor.repos.WebhookSubscriptions.ResetFailures(ctx, subscription.ID); err != nil {
+			return err
+		}
+
+		logger.FromContext(ctx).Info("processed task", "delivery_id", delivery.ID, "response_code", responseCode)
+
+		return processor.repos.WebhookDeliveries.UpdateStatus(ctx, delivery.ID, domain_webhook.DeliveryStatusSucceeded)
+	}
+
+	subscription, err = processor.repos.WebhookSubscriptions.RecordFailure(
+		ctx,
+		subscription.ID,
+		processor.webhookConfig.MaxFailures,
+	)
+	if err != nil {
+		return err
+	}
+
+	if !subscription.IsActive {
+		logger.FromContext(ctx).Error("disabled webhook subscription",
+			"subscription_id", subscription.ID, "failure_count", subscription.FailureCount)
+
+		if err := processor.repos.WebhookDeliveries.UpdateStatus(
+			ctx, delivery.ID, domain_webhook.DeliveryStatusFailed,
+		); err != nil {
+			return err
+		}
+
+		return fmt.Errorf("%w: %w", sendErr, asynq.SkipRetry)
+	}
+
+	if isLastAttempt(ctx) {
+		if err := processor.repos.WebhookDeliveries.UpdateStatus(
+			ctx, delivery.ID, domain_webhook.DeliveryStatusFailed,
+		); err != nil {
+			return err
+		}
+	}
+
+	return sendErr
+}
+
+func (processor *taskHandlers) sendWebhook(
+	ctx context.Context,
+	subscription domain_webhook.Subscription,
+	delivery domain_webhook.Delivery,
+) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set(HeaderWebhookID, delivery.ID.String())
+	req.Header.Set(HeaderWebhookEvent, delivery.EventType)
+	req.Header.Set(HeaderWebhookTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderWebhookSignature, webhook.Sign(subscription.Secret, timestamp, delivery.Payload))
+
+	res, err := processor.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return res.StatusCode, fmt.Errorf("%w: %d", domain.ErrWebhookUnexpectedStatus, res.StatusCode)
+	}
+
+	return res.StatusCode, nil
+}
@@ -0,0 +1,123 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	repository "github.com/b0shka/backend/internal/repository/postgresql"
+	"github.com/b0shka/backend/pkg/logger"
+	"github.com/hibiken/asynq"
+)
+
+const TaskPurgeExpired = "task:purge_expired"
+
+const (
+	purgeTargetVerifyEmails = "verify_emails"
+	purgeTargetSessions     = "sessions"
+
+	defaultPurgeBatchSize = 1000
+)
+
+type PayloadPurgeExpired struct {
+	TaskContext
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskPurgeExpired(
+	ctx context.Context,
+	payload *PayloadPurgeExpired,
+	opts ...asynq.Option,
+) error {
+	return distributor.enqueue(ctx, TaskPurgeExpired, payload, opts...)
+}
+
+func (distributor *RabbitMQTaskDistributor) DistributeTaskPurgeExpired(
+	ctx context.Context,
+	payload *PayloadPurgeExpired,
+	opts ...asynq.Option,
+) error {
+	return distributor.enqueue(ctx, TaskPurgeExpired, payload, opts...)
+}
+
+type deleteExpiredFunc func(ctx context.Context, before time.Time, limit int) (int64, error)
+
+func (processor *taskHandlers) ProcessTaskPurgeExpired(ctx context.Context, task *asynq.Task) error {
+	now := time.Now()
+
+	targets := []struct {
+		name          string
+		retention     time.Duration
+		deleteExpired deleteExpiredFunc
+	}{
+		{
+			name:          purgeTargetVerifyEmails,
+			retention:     processor.cleanupConfig.VerifyEmailsRetention,
+			deleteExpired: processor.repos.VerifyEmails.DeleteExpired,
+		},
+		{
+			name:          purgeTargetSessions,
+			retention:     processor.cleanupConfig.SessionsRetention,
+			deleteExpired: processor.repos.Sessions.DeleteExpired,
+		},
+	}
+
+	for _, target := range targets {
+		err := processor.purgeExpired(ctx, task.Type(), target.name, now.Add(-target.retention), target.deleteExpired)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// purgeExpired deletes the expired rows in batches, so that a large backlog
+// does not hold locks on the table for long, and records the run.
+func (processor *taskHandlers) purgeExpired(
+	ctx context.Context,
+	job, target string,
+	before time.Time,
+	deleteExpired deleteExpiredFunc,
+) error {
+	batchSize := processor.cleanupConfig.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultPurgeBatchSize
+	}
+
+	startedAt := time.Now()
+
+	var removed int64
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		n, err := deleteExpired(ctx, before, batchSize)
+		if err != nil {
+			return err
+		}
+
+		removed += n
+
+		if n < int64(batchSize) {
+			break
+		}
+	}
+
+	jobRunParams := repository.CreateJobRunParams{
+		ID:          processor.idGenerator.GenerateUUID(),
+		Job:         job,
+		Target:      target,
+		RowsRemoved: removed,
+		StartedAt:   startedAt,
+		FinishedAt:  time.Now(),
+	}
+
+	if _, err := processor.repos.JobRuns.Create(ctx, jobRunParams); err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Info("processed task", "job", job, "target", target, "rows_removed", removed)
+
+	return nil
+}
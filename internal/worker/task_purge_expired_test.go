@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/b0shka/backend/internal/config"
+	repository "github.com/b0shka/backend/internal/repository/postgresql"
+	mock_repository "github.com/b0shka/backend/internal/repository/postgresql/mocks"
+	"github.com/b0shka/backend/pkg/identity"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessTaskPurgeExpired(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	verifyEmailsRepo := mock_repository.NewMockVerifyEmails(mockCtl)
+	sessionsRepo := mock_repository.NewMockSessions(mockCtl)
+	jobRunsRepo := mock_repository.NewMockJobRuns(mockCtl)
+
+	cleanupConfig := config.CleanupConfig{
+		BatchSize:             2,
+		VerifyEmailsRetention: time.Hour,
+		SessionsRetention:     time.Hour * 24,
+	}
+	processor := &taskHandlers{
+		repos: &repository.Repositories{
+			VerifyEmails: verifyEmailsRepo,
+			Sessions:     sessionsRepo,
+			JobRuns:      jobRunsRepo,
+		},
+		idGenerator:   &identity.IDGenerator{},
+		cleanupConfig: cleanupConfig,
+	}
+
+	gomock.InOrder(
+		verifyEmailsRepo.EXPECT().DeleteExpired(gomock.Any(), gomock.Any(), 2).Return(int64(2), nil),
+		verifyEmailsRepo.EXPECT().DeleteExpired(gomock.Any(), gomock.Any(), 2).Return(int64(2), nil),
+		verifyEmailsRepo.EXPECT().DeleteExpired(gomock.Any(), gomock.Any(), 2).Return(int64(1), nil),
+	)
+	sessionsRepo.EXPECT().DeleteExpired(gomock.Any(), gomock.Any(), 2).Return(int64(0), nil)
+
+	rowsRemoved := map[string]int64{}
+
+	jobRunsRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, arg repository.CreateJobRunParams) {
+			require.Equal(t, TaskPurgeExpired, arg.Job)
+			require.False(t, arg.FinishedAt.Before(arg.StartedAt))
+
+			rowsRemoved[arg.Target] = arg.RowsRemoved
+		}).
+		Times(2)
+
+	err := processor.ProcessTaskPurgeExpired(context.Background(), asynq.NewTask(TaskPurgeExpired, nil))
+	require.NoError(t, err)
+	require.Equal(t, map[string]int64{
+		purgeTargetVerifyEmails: 5,
+		purgeTargetSessions:     0,
+	}, rowsRemoved)
+}
+
+func TestScheduler_RegisterInvalidSpec(t *testing.T) {
+	scheduler := NewScheduler()
+
+	err := scheduler.Register("not a cron spec", TaskPurgeExpired, func(context.Context, ...asynq.Option) error {
+		return nil
+	})
+	require.Error(t, err)
+}
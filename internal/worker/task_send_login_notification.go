@@ -12,6 +12,7 @@ import (
 const TaskSendLoginNotification = "task:send_login_notification"
 
 type PayloadSendLoginNotification struct {
+	TaskContext
 	Email     string `json:"email"`
 	UserAgent string `json:"user_agent"`
 	ClientIP  string `json:"client_ip"`
@@ -23,42 +24,35 @@ func (distributor *RedisTaskDistributor) DistributeTaskSendLoginNotification(
 	payload *PayloadSendLoginNotification,
 	opts ...asynq.Option,
 ) error {
-	jsonPayload, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("failed to marshal task payload: %w", err)
-	}
-
-	task := asynq.NewTask(TaskSendLoginNotification, jsonPayload, opts...)
-
-	info, err := distributor.client.EnqueueContext(ctx, task)
-	if err != nil {
-		return fmt.Errorf("failed to enqueue task: %w", err)
-	}
-
-	logger.Infof("enqueued task: type - %s, payload - %v, queue - %s, max_retry - %d",
-		task.Type(), payload, info.Queue, info.MaxRetry)
+	return distributor.enqueue(ctx, TaskSendLoginNotification, payload, opts...)
+}
 
-	return nil
+func (distributor *RabbitMQTaskDistributor) DistributeTaskSendLoginNotification(
+	ctx context.Context,
+	payload *PayloadSendLoginNotification,
+	opts ...asynq.Option,
+) error {
+	return distributor.enqueue(ctx, TaskSendLoginNotification, payload, opts...)
 }
 
-func (processor *RedisTaskProcessor) ProcessTaskSendLoginNotification(_ context.Context, task *asynq.Task) error {
+func (processor *taskHandlers) ProcessTaskSendLoginNotification(ctx context.Context, task *asynq.Task) error {
 	var payload PayloadSendLoginNotification
 	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
 		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
 	}
 
-	err := processor.emailService.SendEmail(
+	err := processor.sendEmail(
+		ctx,
 		payload.Email,
+		"login_notification",
 		processor.emailConfig.Templates.LoginNotification,
-		processor.emailConfig.Subjects.LoginNotification,
 		payload,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to send verify email: %w", err)
 	}
 
-	logger.Infof("processed task: type - %s, payload - %v, email - %s",
-		task.Type(), payload, payload.Email)
+	logger.FromContext(ctx).Info("processed task", "payload", payload, "email", payload.Email)
 
 	return nil
 }
@@ -14,8 +14,9 @@ import (
 const TaskSendVerifyEmail = "task:send_verify_email"
 
 type PayloadSendVerifyEmail struct {
+	TaskContext
 	Email      string `json:"email"`
-	SecretCode string `json:"secret_code"`
+	SecretCode string `json:"secret_code" redact:"true"`
 }
 
 func (distributor *RedisTaskDistributor) DistributeTaskSendVerifyEmail(
@@ -23,25 +24,18 @@ func (distributor *RedisTaskDistributor) DistributeTaskSendVerifyEmail(
 	payload *PayloadSendVerifyEmail,
 	opts ...asynq.Option,
 ) error {
-	jsonPayload, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("failed to marshal task payload: %w", err)
-	}
-
-	task := asynq.NewTask(TaskSendVerifyEmail, jsonPayload, opts...)
-
-	info, err := distributor.client.EnqueueContext(ctx, task)
-	if err != nil {
-		return fmt.Errorf("failed to enqueue task: %w", err)
-	}
-
-	logger.Infof("enqueued task: type - %s, payload - %v, queue - %s, max_retry - %d",
-		task.Type(), payload, info.Queue, info.MaxRetry)
+	return distributor.enqueue(ctx, TaskSendVerifyEmail, payload, opts...)
+}
 
-	return nil
+func (distributor *RabbitMQTaskDistributor) DistributeTaskSendVerifyEmail(
+	ctx context.Context,
+	payload *PayloadSendVerifyEmail,
+	opts ...asynq.Option,
+) error {
+	return distributor.enqueue(ctx, TaskSendVerifyEmail, payload, opts...)
 }
 
-func (processor *RedisTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Context, task *asynq.Task) error {
+func (processor *taskHandlers) ProcessTaskSendVerifyEmail(ctx context.Context, task *asynq.Task) error {
 	var payload PayloadSendVerifyEmail
 	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
 		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
@@ -64,18 +58,18 @@ func (processor *RedisTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Cont
 		return err
 	}
 
-	err = processor.emailService.SendEmail(
+	err = processor.sendEmail(
+		ctx,
 		payload.Email,
+		"verify_email",
 		processor.emailConfig.Templates.VerifyEmail,
-		processor.emailConfig.Subjects.VerifyEmail,
 		payload,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to send verify email: %w", err)
 	}
 
-	logger.Infof("processed task: type - %s, payload - %v, email - %s",
-		task.Type(), payload, payload.Email)
+	logger.FromContext(ctx).Info("processed task", "payload", payload, "email", payload.Email)
 
 	return nil
 }
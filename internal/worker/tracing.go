@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/b0shka/backend/pkg/i18n"
+	"github.com/b0shka/backend/pkg/requestid"
+	"github.com/hibiken/asynq"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/b0shka/backend/internal/worker"
+
+// TaskContext is embedded in the task payloads to carry the context of the
+// request that enqueued the task across the broker.
+type TaskContext struct {
+	TraceContext map[string]string `json:"trace_context,omitempty"`
+	RequestID    string            `json:"request_id,omitempty"`
+	Locale       string            `json:"locale,omitempty"`
+}
+
+func (c *TaskContext) taskContext() *TaskContext {
+	return c
+}
+
+type taskContextCarrier interface {
+	taskContext() *TaskContext
+}
+
+// marshalPayload injects the trace context, the request ID and the locale of
+// ctx into the payloads that embed TaskContext and encodes the payload.
+func marshalPayload(ctx context.Context, payload any) ([]byte, error) {
+	if carrier, ok := payload.(taskContextCarrier); ok {
+		traceContext := propagation.MapCarrier{}
+		otel.GetTextMapPropagator().Inject(ctx, traceContext)
+
+		if len(traceContext) > 0 {
+			carrier.taskContext().TraceContext = traceContext
+		}
+
+		carrier.taskContext().RequestID = requestid.FromContext(ctx)
+		carrier.taskContext().Locale = i18n.LocaleFromContext(ctx)
+	}
+
+	return json.Marshal(payload)
+}
+
+// tracingMiddleware starts a span for every task attempt. The span is linked
+// to the span of the request that enqueued the task rather than parented by
+// it, so that retries hours later do not stretch the request trace.
+func tracingMiddleware(next asynq.Handler) asynq.Handler {
+	tracer := otel.Tracer(tracerName)
+
+	return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		opts := []trace.SpanStartOption{
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(attribute.String("task.type", task.Type())),
+		}
+
+		var taskContext TaskContext
+		if err := json.Unmarshal(task.Payload(), &taskContext); err == nil && len(taskContext.TraceContext) > 0 {
+			remote := otel.GetTextMapPropagator().Extract(context.Background(),
+				propagation.MapCarrier(taskContext.TraceContext))
+			opts = append(opts, trace.WithLinks(trace.LinkFromContext(remote)))
+		}
+
+		ctx, span := tracer.Start(ctx, "task "+task.Type(), opts...)
+		defer span.End()
+
+		err := next.ProcessTask(ctx, task)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		return err
+	})
+}
@@ -0,0 +1,114 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/b0shka/backend/pkg/i18n"
+	"github.com/b0shka/backend/pkg/requestid"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var errTest = errors.New("test: error")
+
+func setupTracing(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	return recorder
+}
+
+func TestTaskTracing(t *testing.T) {
+	recorder := setupTracing(t)
+
+	ctx, requestSpan := otel.Tracer("test").Start(context.Background(), "request")
+	payload := &PayloadSendVerifyEmail{Email: "email@ya.ru", SecretCode: "123456"}
+
+	data, err := marshalPayload(ctx, payload)
+	require.NoError(t, err)
+
+	requestSpan.End()
+
+	var decoded PayloadSendVerifyEmail
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Contains(t, decoded.TraceContext, "traceparent")
+	require.Equal(t, payload.Email, decoded.Email)
+
+	handler := tracingMiddleware(asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		return errTest
+	}))
+
+	err = handler.ProcessTask(context.Background(), asynq.NewTask(TaskSendVerifyEmail, data))
+	require.ErrorIs(t, err, errTest)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	taskSpan := spans[1]
+	require.Equal(t, "task "+TaskSendVerifyEmail, taskSpan.Name())
+	require.Equal(t, codes.Error, taskSpan.Status().Code)
+	require.NotEqual(t, requestSpan.SpanContext().TraceID(), taskSpan.SpanContext().TraceID())
+	require.Len(t, taskSpan.Links(), 1)
+	require.Equal(t, requestSpan.SpanContext().SpanID(), taskSpan.Links()[0].SpanContext.SpanID())
+}
+
+func TestMarshalPayloadWithoutSpan(t *testing.T) {
+	setupTracing(t)
+
+	data, err := marshalPayload(context.Background(), &PayloadPurgeExpired{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{}`, string(data))
+}
+
+func TestTaskRequestID(t *testing.T) {
+	ctx := requestid.WithContext(context.Background(), "request")
+
+	data, err := marshalPayload(ctx, &PayloadSendVerifyEmail{Email: "email@ya.ru", SecretCode: "123456"})
+	require.NoError(t, err)
+
+	var taskRequestID string
+
+	handler := loggingMiddleware(asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		taskRequestID = requestid.FromContext(ctx)
+
+		return nil
+	}))
+
+	require.NoError(t, handler.ProcessTask(context.Background(), asynq.NewTask(TaskSendVerifyEmail, data)))
+	require.Equal(t, "request", taskRequestID)
+}
+
+func TestTaskLocale(t *testing.T) {
+	ctx := i18n.WithLocale(context.Background(), "ru")
+
+	data, err := marshalPayload(ctx, &PayloadSendLoginNotification{Email: "email@ya.ru"})
+	require.NoError(t, err)
+
+	var taskLocale string
+
+	handler := loggingMiddleware(asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		taskLocale = i18n.LocaleFromContext(ctx)
+
+		return nil
+	}))
+
+	require.NoError(t, handler.ProcessTask(context.Background(), asynq.NewTask(TaskSendLoginNotification, data)))
+	require.Equal(t, "ru", taskLocale)
+}
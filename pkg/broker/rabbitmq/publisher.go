@@ -0,0 +1,136 @@
+package rabbitmq
+
+import (
+	"context"
+	"sync"
+
+	"github.com/b0shka/backend/internal/domain"
+	"github.com/streadway/amqp"
+)
+
+// Publisher owns a confirm-mode channel that is reopened after the channel or
+// the underlying connection is lost. Setup is invoked for every new channel and
+// is the place to declare exchanges and queues the publisher relies on.
+type Publisher struct {
+	client *Client
+	setup  func(channel *amqp.Channel) error
+
+	mu       sync.Mutex
+	channel  *amqp.Channel
+	confirms chan amqp.Confirmation
+	closed   chan *amqp.Error
+}
+
+func NewPublisher(client *Client, setup func(channel *amqp.Channel) error) *Publisher {
+	return &Publisher{
+		client: client,
+		setup:  setup,
+	}
+}
+
+// Publish sends the message and waits until the broker confirms it.
+func (p *Publisher) Publish(ctx context.Context, exchange, key string, msg amqp.Publishing) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.ensureChannel(); err != nil {
+		return err
+	}
+
+	if err := p.channel.Publish(exchange, key, false, false, msg); err != nil {
+		p.resetChannel()
+
+		return err
+	}
+
+	select {
+	case confirm, ok := <-p.confirms:
+		if !ok || !confirm.Ack {
+			return domain.ErrPublishNotConfirmed
+		}
+	case <-ctx.Done():
+		// the pending confirmation would be read by the next publish,
+		// so the channel is dropped to keep acks in order.
+		p.resetChannel()
+
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+// Declare runs fn on the publisher channel, e.g. to declare a queue on demand.
+func (p *Publisher) Declare(fn func(channel *amqp.Channel) error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.ensureChannel(); err != nil {
+		return err
+	}
+
+	if err := fn(p.channel); err != nil {
+		p.resetChannel()
+
+		return err
+	}
+
+	return nil
+}
+
+// Close closes the channel of the publisher, a later publish opens a new one.
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.channel == nil {
+		return nil
+	}
+
+	err := p.channel.Close()
+	p.channel = nil
+
+	return err
+}
+
+func (p *Publisher) ensureChannel() error {
+	if p.channel != nil {
+		select {
+		case <-p.closed:
+			p.channel = nil
+		default:
+			return nil
+		}
+	}
+
+	channel, err := p.client.Channel()
+	if err != nil {
+		return err
+	}
+
+	if p.setup != nil {
+		if err := p.setup(channel); err != nil {
+			channel.Close()
+
+			return err
+		}
+	}
+
+	if err := channel.Confirm(false); err != nil {
+		channel.Close()
+
+		return err
+	}
+
+	p.channel = channel
+	p.confirms = channel.NotifyPublish(make(chan amqp.Confirmation, 1))
+	p.closed = channel.NotifyClose(make(chan *amqp.Error, 1))
+
+	return nil
+}
+
+func (p *Publisher) resetChannel() {
+	if p.channel != nil {
+		p.channel.Close()
+		p.channel = nil
+	}
+}
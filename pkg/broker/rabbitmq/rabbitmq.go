@@ -0,0 +1,148 @@
+package rabbitmq
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/b0shka/backend/internal/config"
+	"github.com/b0shka/backend/internal/domain"
+	"github.com/b0shka/backend/pkg/logger"
+	"github.com/streadway/amqp"
+)
+
+// Client keeps a single AMQP connection alive and transparently redials it
+// when the broker closes it. Consumers of the client open their own channels
+// and must be ready to reopen them after a reconnect.
+type Client struct {
+	url            string
+	reconnectDelay time.Duration
+
+	mu   sync.RWMutex
+	conn *amqp.Connection
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewClient(cfg config.RabbitMQConfig) (*Client, error) {
+	conn, err := amqp.Dial(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &Client{
+		url:            cfg.URL,
+		reconnectDelay: cfg.ReconnectDelay,
+		conn:           conn,
+		done:           make(chan struct{}),
+	}
+
+	go client.handleReconnect(conn)
+
+	return client, nil
+}
+
+// Channel opens a new channel on the current connection.
+func (c *Client) Channel() (*amqp.Channel, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.conn == nil || c.conn.IsClosed() {
+		return nil, domain.ErrRabbitMQNotConnected
+	}
+
+	return c.conn.Channel()
+}
+
+func (c *Client) IsConnected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.conn != nil && !c.conn.IsClosed()
+}
+
+// Ping reports whether the connection is up, the client redials in the
+// background otherwise.
+func (c *Client) Ping(_ context.Context) error {
+	if !c.IsConnected() {
+		return domain.ErrRabbitMQNotConnected
+	}
+
+	return nil
+}
+
+func (c *Client) Close() error {
+	var err error
+
+	c.closeOnce.Do(func() {
+		close(c.done)
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if c.conn != nil && !c.conn.IsClosed() {
+			err = c.conn.Close()
+		}
+	})
+
+	return err
+}
+
+func (c *Client) handleReconnect(conn *amqp.Connection) {
+	for {
+		notifyClose := conn.NotifyClose(make(chan *amqp.Error, 1))
+
+		select {
+		case <-c.done:
+			return
+		case amqpErr, ok := <-notifyClose:
+			if !ok {
+				return
+			}
+
+			slog.Error("RabbitMQ connection closed", logger.Err(amqpErr))
+		}
+
+		conn = c.redial()
+		if conn == nil {
+			return
+		}
+
+		slog.Info("Success reconnect to RabbitMQ")
+	}
+}
+
+func (c *Client) redial() *amqp.Connection {
+	for {
+		select {
+		case <-c.done:
+			return nil
+		case <-time.After(c.reconnectDelay):
+		}
+
+		conn, err := amqp.Dial(c.url)
+		if err != nil {
+			slog.Error("Failed to reconnect to RabbitMQ, going to do the next attempt", logger.Err(err))
+
+			continue
+		}
+
+		c.mu.Lock()
+
+		select {
+		case <-c.done:
+			c.mu.Unlock()
+			conn.Close()
+
+			return nil
+		default:
+		}
+
+		c.conn = conn
+		c.mu.Unlock()
+
+		return conn
+	}
+}
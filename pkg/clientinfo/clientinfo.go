@@ -0,0 +1,46 @@
+package clientinfo
+
+import (
+	"context"
+
+	"github.com/b0shka/backend/pkg/requestid"
+)
+
+// DeviceIDHeader is the header the clients identify their device with, the
+// gRPC clients send it as the x-device-id metadata.
+const DeviceIDHeader = "X-Device-ID"
+
+type contextKey struct{}
+
+// Info describes the client of a request whatever the transport, the
+// transports fill it in and the services read it from the context. The IP is
+// the address of the client, not of the proxies in front of the service.
+type Info struct {
+	IP        string
+	UserAgent string
+	DeviceID  string
+	RequestID string
+}
+
+// ValidDeviceID reports whether the device ID sent by a client can be kept,
+// it follows the rules of the request IDs as it ends up in the database too.
+func ValidDeviceID(id string) bool {
+	return requestid.Valid(id)
+}
+
+// WithContext stores the client of the request in the context.
+func WithContext(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, contextKey{}, info)
+}
+
+// FromContext returns the client of the request, the request ID falls back
+// to the one of the context, so that the tasks and the jobs that know no
+// client still carry it.
+func FromContext(ctx context.Context) Info {
+	info, _ := ctx.Value(contextKey{}).(Info)
+	if info.RequestID == "" {
+		info.RequestID = requestid.FromContext(ctx)
+	}
+
+	return info
+}
@@ -0,0 +1,37 @@
+package clientinfo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/b0shka/backend/pkg/clientinfo"
+	"github.com/b0shka/backend/pkg/requestid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContext(t *testing.T) {
+	require.Equal(t, clientinfo.Info{}, clientinfo.FromContext(context.Background()))
+
+	info := clientinfo.Info{
+		IP:        "192.0.2.1",
+		UserAgent: "curl/8.0",
+		DeviceID:  "device",
+		RequestID: "request",
+	}
+
+	ctx := requestid.WithContext(context.Background(), "other")
+	require.Equal(t, info, clientinfo.FromContext(clientinfo.WithContext(ctx, info)))
+}
+
+func TestFromContext_RequestID(t *testing.T) {
+	ctx := requestid.WithContext(context.Background(), "request")
+	ctx = clientinfo.WithContext(ctx, clientinfo.Info{IP: "192.0.2.1"})
+
+	require.Equal(t, clientinfo.Info{IP: "192.0.2.1", RequestID: "request"}, clientinfo.FromContext(ctx))
+}
+
+func TestValidDeviceID(t *testing.T) {
+	require.True(t, clientinfo.ValidDeviceID("3f1b6c2e-device"))
+	require.False(t, clientinfo.ValidDeviceID(""))
+	require.False(t, clientinfo.ValidDeviceID("device\nforged=log"))
+}
@@ -0,0 +1,212 @@
+package clientinfo
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// The headers the proxies pass the address of the client in.
+const (
+	HeaderForwarded     = "Forwarded"
+	HeaderXForwardedFor = "X-Forwarded-For"
+	HeaderXRealIP       = "X-Real-IP"
+)
+
+// IPResolver finds the address of the client of a request behind the trusted
+// proxies. The headers are only read when the request comes from one of the
+// proxies, every other client could forge them.
+type IPResolver struct {
+	trusted []netip.Prefix
+	headers []string
+}
+
+// NewIPResolver trusts the proxies at the given addresses or CIDRs, the
+// headers are tried in order until one of them names the client.
+func NewIPResolver(trustedProxies, headers []string) (*IPResolver, error) {
+	trusted := make([]netip.Prefix, 0, len(trustedProxies))
+
+	for _, proxy := range trustedProxies {
+		prefix, err := parsePrefix(proxy)
+		if err != nil {
+			return nil, err
+		}
+
+		trusted = append(trusted, prefix)
+	}
+
+	canonical := make([]string, 0, len(headers))
+
+	for _, header := range headers {
+		header = http.CanonicalHeaderKey(header)
+
+		switch header {
+		case HeaderForwarded, HeaderXForwardedFor, http.CanonicalHeaderKey(HeaderXRealIP):
+		default:
+			return nil, fmt.Errorf("unsupported client ip header: %s", header)
+		}
+
+		canonical = append(canonical, header)
+	}
+
+	return &IPResolver{
+		trusted: trusted,
+		headers: canonical,
+	}, nil
+}
+
+// Resolve returns the address of the client. The chain of a header is walked
+// from the closest hop, the first address that is not a trusted proxy is the
+// client, so that the addresses a client prepends itself are never taken.
+// A malformed header is skipped.
+func (r *IPResolver) Resolve(remoteAddr string, header http.Header) string {
+	remote, ok := parseRemoteAddr(remoteAddr)
+	if !ok {
+		return remoteAddr
+	}
+
+	if !r.isTrusted(remote) {
+		return remote.String()
+	}
+
+	for _, name := range r.headers {
+		chain, ok := headerChain(name, header)
+		if !ok {
+			continue
+		}
+
+		for i := len(chain) - 1; i >= 0; i-- {
+			if i == 0 || !r.isTrusted(chain[i]) {
+				return chain[i].String()
+			}
+		}
+	}
+
+	return remote.String()
+}
+
+func (r *IPResolver) isTrusted(addr netip.Addr) bool {
+	for _, prefix := range r.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parsePrefix(value string) (netip.Prefix, error) {
+	if strings.Contains(value, "/") {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid trusted proxy %q: %w", value, err)
+		}
+
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid trusted proxy %q: %w", value, err)
+	}
+
+	addr = addr.Unmap()
+
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+func parseRemoteAddr(remoteAddr string) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	return parseAddr(host)
+}
+
+// parseAddr accepts an address with or without a port, the IPv6 addresses
+// with a port or from the Forwarded header are in brackets.
+func parseAddr(value string) (netip.Addr, bool) {
+	value = strings.TrimSpace(value)
+
+	if addr, err := netip.ParseAddr(value); err == nil {
+		return addr.Unmap(), true
+	}
+
+	if addrPort, err := netip.ParseAddrPort(value); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		if addr, err := netip.ParseAddr(value[1 : len(value)-1]); err == nil {
+			return addr.Unmap(), true
+		}
+	}
+
+	return netip.Addr{}, false
+}
+
+// headerChain returns the addresses of the header from the client to the
+// closest proxy.
+func headerChain(name string, header http.Header) ([]netip.Addr, bool) {
+	values := header.Values(name)
+	if len(values) == 0 {
+		return nil, false
+	}
+
+	var nodes []string
+
+	switch name {
+	case HeaderForwarded:
+		var ok bool
+		if nodes, ok = forwardedNodes(strings.Join(values, ",")); !ok {
+			return nil, false
+		}
+	case HeaderXForwardedFor:
+		nodes = strings.Split(strings.Join(values, ","), ",")
+	default:
+		nodes = values[:1]
+	}
+
+	chain := make([]netip.Addr, 0, len(nodes))
+
+	for _, node := range nodes {
+		addr, ok := parseAddr(node)
+		if !ok {
+			return nil, false
+		}
+
+		chain = append(chain, addr)
+	}
+
+	return chain, true
+}
+
+// forwardedNodes returns the for parameters of the elements of a RFC 7239
+// Forwarded header. The obfuscated and the unknown nodes make the header
+// unusable, the chain would have a hole.
+func forwardedNodes(value string) ([]string, bool) {
+	elements := strings.Split(value, ",")
+	nodes := make([]string, 0, len(elements))
+
+	for _, element := range elements {
+		var node string
+
+		for _, pair := range strings.Split(element, ";") {
+			key, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+			if found && strings.EqualFold(key, "for") {
+				node = strings.Trim(value, `"`)
+			}
+		}
+
+		if node == "" {
+			return nil, false
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	return nodes, true
+}
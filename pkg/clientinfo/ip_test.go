@@ -0,0 +1,158 @@
+package clientinfo_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/b0shka/backend/pkg/clientinfo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIPResolver(t *testing.T) {
+	_, err := clientinfo.NewIPResolver([]string{"10.0.0.0/8", "192.0.2.1", "::1"}, []string{"x-real-ip"})
+	require.NoError(t, err)
+
+	_, err = clientinfo.NewIPResolver([]string{"10.0.0.0/33"}, nil)
+	require.Error(t, err)
+
+	_, err = clientinfo.NewIPResolver([]string{"nginx"}, nil)
+	require.Error(t, err)
+
+	_, err = clientinfo.NewIPResolver(nil, []string{"X-Client-IP"})
+	require.Error(t, err)
+}
+
+func TestIPResolver_Resolve(t *testing.T) {
+	resolver, err := clientinfo.NewIPResolver(
+		[]string{"10.0.0.0/8", "2001:db8:ffff::/48"},
+		[]string{clientinfo.HeaderForwarded, clientinfo.HeaderXForwardedFor, clientinfo.HeaderXRealIP},
+	)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		remoteAddr string
+		headers    map[string]string
+		ip         string
+	}{
+		"Direct": {
+			remoteAddr: "203.0.113.7:4242",
+			ip:         "203.0.113.7",
+		},
+		"UntrustedPeer": {
+			remoteAddr: "198.51.100.1:4242",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			ip:         "198.51.100.1",
+		},
+		"TrustedPeerWithoutHeaders": {
+			remoteAddr: "10.0.0.2:4242",
+			ip:         "10.0.0.2",
+		},
+		"XForwardedFor": {
+			remoteAddr: "10.0.0.2:4242",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			ip:         "203.0.113.7",
+		},
+		"XForwardedForChain": {
+			remoteAddr: "10.0.0.2:4242",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.7, 10.0.0.3"},
+			ip:         "203.0.113.7",
+		},
+		"XForwardedForSpoofed": {
+			remoteAddr: "10.0.0.2:4242",
+			headers:    map[string]string{"X-Forwarded-For": "1.1.1.1, 203.0.113.7"},
+			ip:         "203.0.113.7",
+		},
+		"XForwardedForOnlyProxies": {
+			remoteAddr: "10.0.0.2:4242",
+			headers:    map[string]string{"X-Forwarded-For": "10.0.0.4, 10.0.0.3"},
+			ip:         "10.0.0.4",
+		},
+		"XForwardedForMalformed": {
+			remoteAddr: "10.0.0.2:4242",
+			headers:    map[string]string{"X-Forwarded-For": "nginx", "X-Real-IP": "203.0.113.7"},
+			ip:         "203.0.113.7",
+		},
+		"XRealIP": {
+			remoteAddr: "10.0.0.2:4242",
+			headers:    map[string]string{"X-Real-IP": "203.0.113.7"},
+			ip:         "203.0.113.7",
+		},
+		"Forwarded": {
+			remoteAddr: "10.0.0.2:4242",
+			headers:    map[string]string{"Forwarded": `for=203.0.113.7;proto=https, for="10.0.0.3:80"`},
+			ip:         "203.0.113.7",
+		},
+		"ForwardedIPv6": {
+			remoteAddr: "[2001:db8:ffff::1]:4242",
+			headers:    map[string]string{"Forwarded": `For="[2001:db8:cafe::17]:4711"`},
+			ip:         "2001:db8:cafe::17",
+		},
+		"ForwardedFirst": {
+			remoteAddr: "10.0.0.2:4242",
+			headers: map[string]string{
+				"Forwarded":       "for=203.0.113.7",
+				"X-Forwarded-For": "198.51.100.1",
+			},
+			ip: "203.0.113.7",
+		},
+		"ForwardedObfuscated": {
+			remoteAddr: "10.0.0.2:4242",
+			headers: map[string]string{
+				"Forwarded":       "for=_hidden",
+				"X-Forwarded-For": "198.51.100.1",
+			},
+			ip: "198.51.100.1",
+		},
+		"MappedIPv4": {
+			remoteAddr: "[::ffff:10.0.0.2]:4242",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			ip:         "203.0.113.7",
+		},
+		"NotAnAddress": {
+			remoteAddr: "bufconn",
+			ip:         "bufconn",
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Run(name, func(t *testing.T) {
+			header := make(http.Header)
+			for key, value := range testCase.headers {
+				header.Set(key, value)
+			}
+
+			require.Equal(t, testCase.ip, resolver.Resolve(testCase.remoteAddr, header))
+		})
+	}
+}
+
+func TestIPResolver_ResolveHeaders(t *testing.T) {
+	resolver, err := clientinfo.NewIPResolver([]string{"10.0.0.0/8"}, []string{clientinfo.HeaderXRealIP})
+	require.NoError(t, err)
+
+	header := make(http.Header)
+	header.Set("X-Forwarded-For", "198.51.100.1")
+	header.Set("X-Real-IP", "203.0.113.7")
+
+	require.Equal(t, "203.0.113.7", resolver.Resolve("10.0.0.2:4242", header))
+
+	header.Del("X-Real-IP")
+	require.Equal(t, "10.0.0.2", resolver.Resolve("10.0.0.2:4242", header))
+}
+
+func TestIPResolver_ResolveSpoofedForwarded(t *testing.T) {
+	resolver, err := clientinfo.NewIPResolver(
+		[]string{"172.16.0.0/12"},
+		[]string{clientinfo.HeaderXForwardedFor, clientinfo.HeaderXRealIP},
+	)
+	require.NoError(t, err)
+
+	// nginx appends the address of the client to X-Forwarded-For and passes
+	// the Forwarded header of the client through.
+	header := make(http.Header)
+	header.Set("Forwarded", "for=6.6.6.6")
+	header.Set("X-Forwarded-For", "203.0.113.7")
+	header.Set("X-Real-IP", "203.0.113.7")
+
+	require.Equal(t, "203.0.113.7", resolver.Resolve("172.18.0.3:4242", header))
+}
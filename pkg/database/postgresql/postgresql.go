@@ -30,14 +30,16 @@ func NewClient(ctx context.Context, cfg config.PostgresConfig) (*pgxpool.Pool, e
 
 	pgxCfg, parseConfigErr := pgxpool.ParseConfig(cfg.URL)
 	if parseConfigErr != nil {
-		logger.Errorf("Unable to parse config: %v\n", parseConfigErr)
+		logger.FromContext(ctx).Error("Unable to parse config", logger.Err(parseConfigErr))
 
 		return nil, parseConfigErr
 	}
 
+	pgxCfg.ConnConfig.Tracer = NewQueryTracer()
+
 	pool, parseConfigErr = pgxpool.NewWithConfig(ctx, pgxCfg)
 	if parseConfigErr != nil {
-		logger.Errorf("Failed to parse PostgreSQL configuration due to error: %v\n", parseConfigErr)
+		logger.FromContext(ctx).Error("Failed to create PostgreSQL pool", logger.Err(parseConfigErr))
 
 		return nil, parseConfigErr
 	}
@@ -45,7 +47,7 @@ func NewClient(ctx context.Context, cfg config.PostgresConfig) (*pgxpool.Pool, e
 	err := DoWithAttempts(func() error {
 		pingErr := pool.Ping(ctx)
 		if pingErr != nil {
-			logger.Errorf("Failed to connect to postgres due to error %v... Going to do the next attempt\n", pingErr)
+			logger.FromContext(ctx).Error("Failed to connect to postgres, going to do the next attempt", logger.Err(pingErr))
 
 			return pingErr
 		}
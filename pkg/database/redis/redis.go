@@ -0,0 +1,29 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/b0shka/backend/internal/config"
+	"github.com/redis/go-redis/extra/redisotel/v9"
+	"github.com/redis/go-redis/v9"
+)
+
+func NewClient(ctx context.Context, cfg config.RedisConfig) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr: cfg.Address,
+	})
+
+	if err := redisotel.InstrumentTracing(client); err != nil {
+		client.Close()
+
+		return nil, err
+	}
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+
+		return nil, err
+	}
+
+	return client, nil
+}
@@ -2,14 +2,24 @@ package email
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"net"
 	"net/smtp"
+	"path/filepath"
+	"strconv"
 	"text/template"
 
 	"github.com/b0shka/backend/internal/domain"
 	"github.com/jordan-wright/email"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const tracerName = "github.com/b0shka/backend/pkg/email"
+
 type EmailService struct { //nolint:revive
 	Name     string
 	Email    string
@@ -28,10 +38,68 @@ func NewEmailService(name, email, password, host string, port int) *EmailService
 	}
 }
 
-func (s *EmailService) SendEmail(toEmail, templateFile, subject string, contentData any) error {
+// Ping dials the smtp server and waits for its greeting.
+func (s *EmailService) Ping(ctx context.Context) error {
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.Host, strconv.Itoa(s.Port)))
+	if err != nil {
+		return err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+
+			return err
+		}
+	}
+
+	client, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+
+		return err
+	}
+
+	return client.Quit()
+}
+
+// SendEmail renders the template with the functions and sends it over smtp,
+// the span covers the rendering and the whole smtp exchange.
+func (s *EmailService) SendEmail(
+	ctx context.Context,
+	toEmail, templateFile, subject string,
+	contentData any,
+	funcs template.FuncMap,
+) error {
+	_, span := otel.Tracer(tracerName).Start(ctx, "EmailService.SendEmail",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("email.template", templateFile),
+			attribute.String("smtp.host", s.Host),
+		),
+	)
+	defer span.End()
+
+	if err := s.sendEmail(toEmail, templateFile, subject, contentData, funcs); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *EmailService) sendEmail(
+	toEmail, templateFile, subject string,
+	contentData any,
+	funcs template.FuncMap,
+) error {
 	var content bytes.Buffer
 
-	contentHTML, err := template.ParseFiles(templateFile)
+	contentHTML, err := template.New(filepath.Base(templateFile)).Funcs(funcs).ParseFiles(templateFile)
 	if err != nil {
 		return err
 	}
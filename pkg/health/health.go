@@ -0,0 +1,148 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/b0shka/backend/pkg/logger"
+)
+
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusDraining = "draining"
+
+	errTimeout     = "timeout"
+	errUnavailable = "unavailable"
+)
+
+// CheckFunc reports whether a dependency can serve requests, it must give up
+// when the context is done.
+type CheckFunc func(ctx context.Context) error
+
+// Result is the outcome of the check of a dependency. The error is coarse on
+// purpose, the probes are not authenticated and the details are logged only.
+type Result struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report is the readiness of the service with the result of every check.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Probe checks the dependencies of the service concurrently, every check is
+// bounded by the timeout. Once drained the probe reports not ready without
+// checking anything, so that the traffic moves away before the shutdown.
+type Probe struct {
+	timeout  time.Duration
+	checks   []check
+	draining atomic.Bool
+}
+
+func NewProbe(timeout time.Duration) *Probe {
+	return &Probe{
+		timeout: timeout,
+	}
+}
+
+// Register adds the check of a dependency, it is not safe to call once the
+// probe serves requests.
+func (p *Probe) Register(name string, fn CheckFunc) {
+	p.checks = append(p.checks, check{name: name, fn: fn})
+}
+
+// Drain makes the probe report not ready for good.
+func (p *Probe) Drain() {
+	p.draining.Store(true)
+}
+
+// Check runs the checks and reports whether every dependency is up.
+func (p *Probe) Check(ctx context.Context) (Report, bool) {
+	if p.draining.Load() {
+		return Report{Status: StatusDraining}, false
+	}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+
+	report := Report{
+		Status: StatusUp,
+		Checks: make(map[string]Result, len(p.checks)),
+	}
+
+	for _, c := range p.checks {
+		wg.Add(1)
+
+		go func(c check) {
+			defer wg.Done()
+
+			res := p.run(ctx, c)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Checks[c.name] = res
+			if res.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}(c)
+	}
+
+	wg.Wait()
+
+	return report, report.Status == StatusUp
+}
+
+func (p *Probe) run(ctx context.Context, c check) Result {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+
+	// the check is not waited for past the timeout even if it ignores the
+	// context.
+	go func() {
+		done <- c.fn(ctx)
+	}()
+
+	var err error
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	res := Result{
+		Status:   StatusUp,
+		Duration: time.Since(start).Round(time.Millisecond).String(),
+	}
+
+	if err != nil {
+		res.Status = StatusDown
+		res.Error = errUnavailable
+
+		if errors.Is(err, context.DeadlineExceeded) {
+			res.Error = errTimeout
+		}
+
+		logger.FromContext(ctx).Warn("health check failed", "check", c.name, logger.Err(err))
+	}
+
+	return res
+}
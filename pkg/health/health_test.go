@@ -0,0 +1,85 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/b0shka/backend/pkg/health"
+	"github.com/stretchr/testify/require"
+)
+
+func up(context.Context) error {
+	return nil
+}
+
+func TestProbe_Check(t *testing.T) {
+	tests := map[string]struct {
+		check  health.CheckFunc
+		ready  bool
+		status string
+		err    string
+	}{
+		"Up": {
+			check:  up,
+			ready:  true,
+			status: health.StatusUp,
+		},
+		"Down": {
+			check: func(context.Context) error {
+				return errors.New("dial tcp 10.0.0.1:5432: connection refused")
+			},
+			status: health.StatusDown,
+			err:    "unavailable",
+		},
+		"Timeout": {
+			check: func(ctx context.Context) error {
+				<-ctx.Done()
+
+				return ctx.Err()
+			},
+			status: health.StatusDown,
+			err:    "timeout",
+		},
+		"IgnoresContext": {
+			check: func(context.Context) error {
+				time.Sleep(time.Second)
+
+				return nil
+			},
+			status: health.StatusDown,
+			err:    "timeout",
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Run(name, func(t *testing.T) {
+			probe := health.NewProbe(time.Millisecond * 50)
+			probe.Register("postgresql", up)
+			probe.Register("redis", testCase.check)
+
+			report, ready := probe.Check(context.Background())
+			require.Equal(t, testCase.ready, ready)
+			require.Equal(t, testCase.status, report.Status)
+			require.Equal(t, health.StatusUp, report.Checks["postgresql"].Status)
+			require.Equal(t, testCase.status, report.Checks["redis"].Status)
+			require.Equal(t, testCase.err, report.Checks["redis"].Error)
+		})
+	}
+}
+
+func TestProbe_Drain(t *testing.T) {
+	probe := health.NewProbe(time.Second)
+	probe.Register("postgresql", func(context.Context) error {
+		t.Fatal("a drained probe must not check the dependencies")
+
+		return nil
+	})
+
+	probe.Drain()
+
+	report, ready := probe.Check(context.Background())
+	require.False(t, ready)
+	require.Equal(t, health.Report{Status: health.StatusDraining}, report)
+}
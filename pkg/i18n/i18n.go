@@ -0,0 +1,203 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/b0shka/backend/internal/domain"
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+// Catalog maps the message keys, such as "errors.invalid_input", to the
+// messages of a locale.
+type Catalog map[string]string
+
+// Bundle holds the catalogs of the supported locales. A message missing in
+// the catalog of a locale falls back to the catalog of the default locale,
+// and to its key when the default catalog misses it too.
+type Bundle struct {
+	defaultLocale string
+	locales       []string
+	catalogs      map[string]Catalog
+	matcher       language.Matcher
+}
+
+type contextKey struct{}
+
+// NewBundle creates a bundle of the catalogs keyed by locale, the catalog of
+// the default locale is required.
+func NewBundle(defaultLocale string, catalogs map[string]Catalog) (*Bundle, error) {
+	if _, ok := catalogs[defaultLocale]; !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnsupportedLocale, defaultLocale)
+	}
+
+	// the default locale comes first, the matcher falls back to it.
+	locales := []string{defaultLocale}
+	for locale := range catalogs {
+		if locale != defaultLocale {
+			locales = append(locales, locale)
+		}
+	}
+
+	sort.Strings(locales[1:])
+
+	tags := make([]language.Tag, 0, len(locales))
+
+	for _, locale := range locales {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", domain.ErrUnsupportedLocale, locale)
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return &Bundle{
+		defaultLocale: defaultLocale,
+		locales:       locales,
+		catalogs:      catalogs,
+		matcher:       language.NewMatcher(tags),
+	}, nil
+}
+
+// LoadDir loads the catalogs from the yaml files of the directory, the name
+// of a file is its locale, such as "en.yml". Nested keys are joined with
+// dots.
+func LoadDir(dir, defaultLocale string) (*Bundle, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yml"))
+	if err != nil {
+		return nil, err
+	}
+
+	catalogs := make(map[string]Catalog, len(files))
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var doc map[string]any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse catalog %s: %w", file, err)
+		}
+
+		catalog := make(Catalog)
+		flatten("", doc, catalog)
+		catalogs[strings.TrimSuffix(filepath.Base(file), ".yml")] = catalog
+	}
+
+	return NewBundle(defaultLocale, catalogs)
+}
+
+// DefaultLocale returns the locale used when no preference matches.
+func (b *Bundle) DefaultLocale() string {
+	return b.defaultLocale
+}
+
+// Locales returns the supported locales, the default one first.
+func (b *Bundle) Locales() []string {
+	return b.locales
+}
+
+// Supports reports whether the bundle has a catalog of the locale.
+func (b *Bundle) Supports(locale string) bool {
+	_, ok := b.catalogs[locale]
+
+	return ok
+}
+
+// Match negotiates the locale from the preferences in order of priority, a
+// preference is either a locale or an Accept-Language header. The first
+// preference that matches a supported locale wins, the default locale is
+// returned when none does.
+func (b *Bundle) Match(preferences ...string) string {
+	for _, preference := range preferences {
+		if preference == "" {
+			continue
+		}
+
+		tags, _, err := language.ParseAcceptLanguage(preference)
+		if err != nil || len(tags) == 0 {
+			continue
+		}
+
+		_, index, confidence := b.matcher.Match(tags...)
+		if confidence > language.No {
+			return b.locales[index]
+		}
+	}
+
+	return b.defaultLocale
+}
+
+// Lookup returns the message of the key in the locale, or in the default
+// locale when the locale misses it.
+func (b *Bundle) Lookup(locale, key string) (string, bool) {
+	if message, ok := b.catalogs[locale][key]; ok {
+		return message, true
+	}
+
+	message, ok := b.catalogs[b.defaultLocale][key]
+
+	return message, ok
+}
+
+// Translate returns the message of the key in the locale formatted with the
+// args, the key itself is returned when no catalog has it.
+func (b *Bundle) Translate(locale, key string, args ...any) string {
+	message, ok := b.Lookup(locale, key)
+	if !ok {
+		return key
+	}
+
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+
+	return message
+}
+
+// Funcs returns the template functions that translate to the locale, the
+// templates call {{ t "email.verify_email.title" }}.
+func (b *Bundle) Funcs(locale string) template.FuncMap {
+	return template.FuncMap{
+		"t": func(key string, args ...any) string {
+			return b.Translate(locale, key, args...)
+		},
+	}
+}
+
+// WithLocale stores the locale negotiated for the request in the context.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// LocaleFromContext returns the locale of the context, or an empty string
+// when the context carries none.
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(contextKey{}).(string)
+
+	return locale
+}
+
+func flatten(prefix string, doc map[string]any, catalog Catalog) {
+	for key, value := range doc {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch value := value.(type) {
+		case map[string]any:
+			flatten(key, value, catalog)
+		default:
+			catalog[key] = fmt.Sprint(value)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package i18n_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"text/template"
+
+	"github.com/b0shka/backend/internal/domain"
+	"github.com/b0shka/backend/pkg/i18n"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func newBundle(t *testing.T) *i18n.Bundle {
+	t.Helper()
+
+	bundle, err := i18n.NewBundle("en", map[string]i18n.Catalog{
+		"en": {
+			"errors.invalid_input": "The request body is invalid.",
+			"errors.invalid_code":  "The code is incorrect.",
+			"email.body":           "Hello, %s",
+		},
+		"ru": {
+			"errors.invalid_input": "Некорректное тело запроса.",
+			"email.body":           "Привет, %s",
+		},
+	})
+	require.NoError(t, err)
+
+	return bundle
+}
+
+func TestNewBundle_MissingDefault(t *testing.T) {
+	_, err := i18n.NewBundle("en", map[string]i18n.Catalog{"ru": {}})
+	require.ErrorIs(t, err, domain.ErrUnsupportedLocale)
+}
+
+func TestBundle_Locales(t *testing.T) {
+	bundle := newBundle(t)
+
+	require.Equal(t, "en", bundle.DefaultLocale())
+	require.Equal(t, []string{"en", "ru"}, bundle.Locales())
+	require.True(t, bundle.Supports("ru"))
+	require.False(t, bundle.Supports("de"))
+}
+
+func TestBundle_Match(t *testing.T) {
+	bundle := newBundle(t)
+
+	tests := map[string]struct {
+		preferences []string
+		locale      string
+	}{
+		"Empty":           {nil, "en"},
+		"Exact":           {[]string{"ru"}, "ru"},
+		"Region":          {[]string{"ru-RU"}, "ru"},
+		"QualityValues":   {[]string{"de;q=0.9,ru;q=0.8,en;q=0.1"}, "ru"},
+		"Unsupported":     {[]string{"de-DE"}, "en"},
+		"Malformed":       {[]string{"!!"}, "en"},
+		"SavedPreference": {[]string{"ru", "en-US"}, "ru"},
+		"EmptyPreference": {[]string{"", "ru"}, "ru"},
+		"NextPreference":  {[]string{"de", "ru"}, "ru"},
+	}
+
+	for name, testCase := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, testCase.locale, bundle.Match(testCase.preferences...))
+		})
+	}
+}
+
+func TestBundle_Translate(t *testing.T) {
+	bundle := newBundle(t)
+
+	tests := map[string]struct {
+		locale  string
+		key     string
+		args    []any
+		message string
+	}{
+		"Translated":        {"ru", "errors.invalid_input", nil, "Некорректное тело запроса."},
+		"Formatted":         {"ru", "email.body", []any{"email@ya.ru"}, "Привет, email@ya.ru"},
+		"MissingInLocale":   {"ru", "errors.invalid_code", nil, "The code is incorrect."},
+		"UnsupportedLocale": {"de", "errors.invalid_input", nil, "The request body is invalid."},
+		"MissingKey":        {"ru", "errors.unknown", nil, "errors.unknown"},
+	}
+
+	for name, testCase := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, testCase.message, bundle.Translate(testCase.locale, testCase.key, testCase.args...))
+		})
+	}
+}
+
+func TestBundle_Lookup(t *testing.T) {
+	bundle := newBundle(t)
+
+	message, ok := bundle.Lookup("ru", "errors.invalid_code")
+	require.True(t, ok)
+	require.Equal(t, "The code is incorrect.", message)
+
+	_, ok = bundle.Lookup("ru", "errors.unknown")
+	require.False(t, ok)
+}
+
+func TestBundle_Funcs(t *testing.T) {
+	bundle := newBundle(t)
+
+	tmpl, err := template.New("email").Funcs(bundle.Funcs("ru")).Parse(`{{ t "email.body" .Email }}`)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, tmpl.Execute(&buf, map[string]string{"Email": "email@ya.ru"}))
+	require.Equal(t, "Привет, email@ya.ru", buf.String())
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "en.yml"), []byte("errors:\n  invalid_input: invalid\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ru.yml"), []byte("errors:\n  invalid_input: некорректно\n"), 0o600))
+
+	bundle, err := i18n.LoadDir(dir, "en")
+	require.NoError(t, err)
+	require.Equal(t, "некорректно", bundle.Translate("ru", "errors.invalid_input"))
+
+	_, err = i18n.LoadDir(dir, "de")
+	require.ErrorIs(t, err, domain.ErrUnsupportedLocale)
+}
+
+// TestShippedCatalogs keeps the shipped catalogs in sync, a key missing in
+// one of them would silently fall back to the default locale.
+func TestShippedCatalogs(t *testing.T) {
+	files, err := filepath.Glob("../../locales/*.yml")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	keys := make(map[string][]string, len(files))
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		var doc map[string]any
+		require.NoError(t, yaml.Unmarshal(data, &doc))
+
+		keys[filepath.Base(file)] = catalogKeys("", doc)
+	}
+
+	for file := range keys {
+		require.Equal(t, keys["en.yml"], keys[file], file)
+	}
+
+	_, err = i18n.LoadDir("../../locales", "en")
+	require.NoError(t, err)
+}
+
+func catalogKeys(prefix string, doc map[string]any) []string {
+	var res []string
+
+	for key, value := range doc {
+		if nested, ok := value.(map[string]any); ok {
+			res = append(res, catalogKeys(prefix+key+".", nested)...)
+		} else {
+			res = append(res, prefix+key)
+		}
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+func TestContext(t *testing.T) {
+	require.Empty(t, i18n.LocaleFromContext(context.Background()))
+	require.Equal(t, "ru", i18n.LocaleFromContext(i18n.WithLocale(context.Background(), "ru")))
+}
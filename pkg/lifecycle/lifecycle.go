@@ -0,0 +1,129 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/b0shka/backend/pkg/logger"
+)
+
+// Component is a part of the application that has to be stopped, such as a
+// client, a server or a worker. Start is nil for the components started by
+// their constructor, such as the clients that connect when created.
+type Component struct {
+	Name  string
+	Start func() error
+	Stop  func(ctx context.Context) error
+	// Timeout bounds the stop of the component, the default timeout of the
+	// manager is used when it is zero.
+	Timeout time.Duration
+}
+
+type component struct {
+	Component
+	started bool
+}
+
+// Manager starts the components in the order they are appended and stops
+// them in the reverse order, so a component has to be appended after the
+// components it depends on. A component that does not stop within its
+// timeout is left behind and the next one is stopped.
+type Manager struct {
+	timeout    time.Duration
+	components []*component
+}
+
+func NewManager(timeout time.Duration) *Manager {
+	return &Manager{
+		timeout: timeout,
+	}
+}
+
+// Append registers the component, it is not safe to call concurrently with
+// Start or Stop.
+func (m *Manager) Append(c Component) {
+	m.components = append(m.components, &component{
+		Component: c,
+		started:   c.Start == nil,
+	})
+}
+
+// Start starts the components that are not started yet. It stops at the
+// first component that fails, Stop is to be called then to release the
+// components already started.
+func (m *Manager) Start() error {
+	for _, c := range m.components {
+		if c.started {
+			continue
+		}
+
+		if err := c.Start(); err != nil {
+			return fmt.Errorf("failed to start %s: %w", c.Name, err)
+		}
+
+		c.started = true
+
+		slog.Info("Component started", "component", c.Name)
+	}
+
+	return nil
+}
+
+// Stop stops the started components in the reverse order. A component that
+// fails to stop does not keep the others from stopping, the errors are
+// logged and joined.
+func (m *Manager) Stop(ctx context.Context) error {
+	var errs []error
+
+	for i := len(m.components) - 1; i >= 0; i-- {
+		c := m.components[i]
+		if !c.started {
+			continue
+		}
+
+		c.started = false
+
+		if c.Stop == nil {
+			continue
+		}
+
+		if err := m.stop(ctx, c); err != nil {
+			slog.Error("failed to stop component", "component", c.Name, logger.Err(err))
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", c.Name, err))
+
+			continue
+		}
+
+		slog.Info("Component stopped", "component", c.Name)
+	}
+
+	return errors.Join(errs...)
+}
+
+// stop waits for the component until its timeout passes even when the stop
+// does not watch the context, e.g. a pool that waits for its connections.
+func (m *Manager) stop(ctx context.Context, c *component) error {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = m.timeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	stopped := make(chan error, 1)
+
+	go func() {
+		stopped <- c.Stop(ctx)
+	}()
+
+	select {
+	case err := <-stopped:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
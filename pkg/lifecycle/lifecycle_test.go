@@ -0,0 +1,115 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/b0shka/backend/pkg/lifecycle"
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	events []string
+}
+
+func (r *recorder) component(name string, startErr error) lifecycle.Component {
+	return lifecycle.Component{
+		Name: name,
+		Start: func() error {
+			r.events = append(r.events, "start "+name)
+
+			return startErr
+		},
+		Stop: func(context.Context) error {
+			r.events = append(r.events, "stop "+name)
+
+			return nil
+		},
+	}
+}
+
+func TestManager(t *testing.T) {
+	var r recorder
+
+	manager := lifecycle.NewManager(time.Second)
+	manager.Append(lifecycle.Component{
+		Name: "client",
+		Stop: func(context.Context) error {
+			r.events = append(r.events, "stop client")
+
+			return nil
+		},
+	})
+	manager.Append(r.component("worker", nil))
+	manager.Append(r.component("server", nil))
+
+	require.NoError(t, manager.Start())
+	require.NoError(t, manager.Stop(context.Background()))
+	require.NoError(t, manager.Stop(context.Background()))
+
+	require.Equal(t, []string{
+		"start worker",
+		"start server",
+		"stop server",
+		"stop worker",
+		"stop client",
+	}, r.events)
+}
+
+func TestManager_StartFailed(t *testing.T) {
+	var r recorder
+
+	manager := lifecycle.NewManager(time.Second)
+	manager.Append(r.component("worker", nil))
+	manager.Append(r.component("server", errors.New("address already in use")))
+	manager.Append(r.component("gateway", nil))
+
+	require.ErrorContains(t, manager.Start(), "failed to start server")
+	require.NoError(t, manager.Stop(context.Background()))
+
+	require.Equal(t, []string{
+		"start worker",
+		"start server",
+		"stop worker",
+	}, r.events)
+}
+
+func TestManager_StopTimeout(t *testing.T) {
+	var stopped []string
+
+	manager := lifecycle.NewManager(time.Millisecond * 10)
+	manager.Append(lifecycle.Component{
+		Name: "database",
+		Stop: func(context.Context) error {
+			stopped = append(stopped, "database")
+
+			return nil
+		},
+	})
+	manager.Append(lifecycle.Component{
+		Name: "worker",
+		Stop: func(context.Context) error {
+			// ignores the context, as a pool that waits for its connections.
+			time.Sleep(time.Second)
+
+			return nil
+		},
+	})
+	manager.Append(lifecycle.Component{
+		Name:    "server",
+		Timeout: time.Second,
+		Stop: func(context.Context) error {
+			return errors.New("listener closed")
+		},
+	})
+
+	start := time.Now()
+	err := manager.Stop(context.Background())
+
+	require.Less(t, time.Since(start), time.Millisecond*500)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, "failed to stop server: listener closed")
+	require.Equal(t, []string{"database"}, stopped)
+}
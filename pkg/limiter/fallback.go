@@ -0,0 +1,32 @@
+package limiter
+
+import (
+	"context"
+
+	"github.com/b0shka/backend/pkg/logger"
+)
+
+// FallbackLimiter uses the fallback limiter whenever the primary one fails,
+// e.g. while redis is unavailable.
+type FallbackLimiter struct {
+	primary  Limiter
+	fallback Limiter
+}
+
+func NewFallbackLimiter(primary, fallback Limiter) *FallbackLimiter {
+	return &FallbackLimiter{
+		primary:  primary,
+		fallback: fallback,
+	}
+}
+
+func (l *FallbackLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	res, err := l.primary.Allow(ctx, key, limit)
+	if err == nil {
+		return res, nil
+	}
+
+	logger.FromContext(ctx).Error("rate limiter failed, using fallback", logger.Err(err))
+
+	return l.fallback.Allow(ctx, key, limit)
+}
@@ -0,0 +1,61 @@
+package limiter
+
+import (
+	"context"
+	"time"
+)
+
+// Limit allows Requests per Period with bursts of up to Requests.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter is the time until the limit is fully replenished.
+	ResetAfter time.Duration
+	// RetryAfter is the time until the next request is allowed, zero when
+	// the request is allowed.
+	RetryAfter time.Duration
+}
+
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// gcra applies the generic cell rate algorithm to the theoretical arrival
+// time of the key and returns the new one, which only has to be stored when
+// the request is allowed.
+func gcra(now, tat time.Time, limit Limit) (Result, time.Time) {
+	if tat.Before(now) {
+		tat = now
+	}
+
+	interval := limit.interval()
+	newTat := tat.Add(interval)
+	allowAt := newTat.Add(-limit.Period)
+
+	if now.Before(allowAt) {
+		return Result{
+			Allowed:    false,
+			Limit:      limit.Requests,
+			Remaining:  0,
+			ResetAfter: tat.Sub(now),
+			RetryAfter: allowAt.Sub(now),
+		}, tat
+	}
+
+	return Result{
+		Allowed:    true,
+		Limit:      limit.Requests,
+		Remaining:  int(now.Sub(allowAt) / interval),
+		ResetAfter: newTat.Sub(now),
+	}, newTat
+}
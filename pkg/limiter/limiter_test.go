@@ -0,0 +1,92 @@
+package limiter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func (c *clock) Add(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func testLimiter(t *testing.T, limiter Limiter, clock *clock) {
+	ctx := context.Background()
+	limit := Limit{Requests: 3, Period: 3 * time.Second}
+
+	for i := 2; i >= 0; i-- {
+		res, err := limiter.Allow(ctx, "key", limit)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+		require.Equal(t, 3, res.Limit)
+		require.Equal(t, i, res.Remaining)
+	}
+
+	res, err := limiter.Allow(ctx, "key", limit)
+	require.NoError(t, err)
+	require.False(t, res.Allowed)
+	require.Equal(t, 0, res.Remaining)
+	require.Equal(t, time.Second, res.RetryAfter)
+	require.Equal(t, 3*time.Second, res.ResetAfter)
+
+	res, err = limiter.Allow(ctx, "other", limit)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+
+	clock.Add(time.Second)
+
+	res, err = limiter.Allow(ctx, "key", limit)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+	require.Equal(t, 0, res.Remaining)
+}
+
+func TestMemoryLimiter(t *testing.T) {
+	clock := &clock{now: time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)}
+
+	limiter := NewMemoryLimiter()
+	limiter.now = clock.Now
+
+	testLimiter(t, limiter, clock)
+}
+
+func TestRedisLimiter(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+
+	defer client.Close()
+
+	clock := &clock{now: time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)}
+
+	limiter := NewRedisLimiter(client, "rate_limit:")
+	limiter.now = clock.Now
+
+	testLimiter(t, limiter, clock)
+}
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string, Limit) (Result, error) {
+	return Result{}, errors.New("redis is down")
+}
+
+func TestFallbackLimiter(t *testing.T) {
+	limiter := NewFallbackLimiter(failingLimiter{}, NewMemoryLimiter())
+
+	res, err := limiter.Allow(context.Background(), "key", Limit{Requests: 1, Period: time.Minute})
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+}
@@ -0,0 +1,55 @@
+package limiter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const sweepInterval = time.Minute
+
+// MemoryLimiter keeps the state in the process, so every instance of the
+// service limits on its own. It is meant as a fallback for the redis limiter.
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	tats      map[string]time.Time
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		tats: make(map[string]time.Time),
+		now:  time.Now,
+	}
+}
+
+func (l *MemoryLimiter) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	res, tat := gcra(now, l.tats[key], limit)
+	if res.Allowed {
+		l.tats[key] = tat
+	}
+
+	return res, nil
+}
+
+// sweep drops the keys whose limit is fully replenished.
+func (l *MemoryLimiter) sweep(now time.Time) {
+	if now.Before(l.nextSweep) {
+		return
+	}
+
+	for key, tat := range l.tats {
+		if !tat.After(now) {
+			delete(l.tats, key)
+		}
+	}
+
+	l.nextSweep = now.Add(sweepInterval)
+}
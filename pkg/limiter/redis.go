@@ -0,0 +1,72 @@
+package limiter
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// allowScript is the redis counterpart of gcra, times are in microseconds.
+//
+// KEYS: theoretical arrival time of the key.
+// ARGV: now, emission interval, period.
+const allowScript = `
+local now = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local period = tonumber(ARGV[3])
+
+local tat = tonumber(redis.call('GET', KEYS[1]) or ARGV[1])
+if tat < now then
+	tat = now
+end
+
+local newTat = tat + interval
+local allowAt = newTat - period
+
+if now < allowAt then
+	return {0, 0, tat - now, allowAt - now}
+end
+
+redis.call('SET', KEYS[1], string.format('%d', newTat), 'PX', math.ceil((newTat - now) / 1000))
+
+return {1, math.floor((now - allowAt) / interval), newTat - now, 0}
+`
+
+type RedisLimiter struct {
+	client *redis.Client
+	script *redis.Script
+	prefix string
+	now    func() time.Time
+}
+
+func NewRedisLimiter(client *redis.Client, prefix string) *RedisLimiter {
+	return &RedisLimiter{
+		client: client,
+		script: redis.NewScript(allowScript),
+		prefix: prefix,
+		now:    time.Now,
+	}
+}
+
+func (l *RedisLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	res, err := l.script.Run(
+		ctx,
+		l.client,
+		[]string{l.prefix + key},
+		l.now().UnixMicro(),
+		limit.interval().Microseconds(),
+		limit.Period.Microseconds(),
+	).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Allowed:    res[0] == 1,
+		Limit:      limit.Requests,
+		Remaining:  int(res[1]),
+		ResetAfter: time.Duration(res[2]) * time.Microsecond,
+		RetryAfter: time.Duration(res[3]) * time.Microsecond,
+	}, nil
+}
@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// levelHandler filters the records by the level configured for the package
+// that logged them, the package is resolved from the program counter of the
+// record and falls back to the default level. The default level can be
+// changed at runtime, the handlers derived with attributes share it.
+type levelHandler struct {
+	next   slog.Handler
+	levels *packageLevels
+}
+
+type packageLevels struct {
+	level  *slog.LevelVar
+	levels map[string]slog.Level
+	// cache maps the program counters to the matched package levels.
+	cache sync.Map
+}
+
+type packageLevel struct {
+	level   slog.Level
+	matched bool
+}
+
+func newLevelHandler(next slog.Handler, levels *packageLevels) *levelHandler {
+	return &levelHandler{
+		next:   next,
+		levels: levels,
+	}
+}
+
+func newPackageLevels(level slog.Level, levels map[string]slog.Level) *packageLevels {
+	l := &packageLevels{levels: levels, level: new(slog.LevelVar)}
+	l.level.Set(level)
+
+	return l
+}
+
+// Level is the lowest level any package logs at, the records below it are
+// dropped before they are built.
+func (l *packageLevels) Level() slog.Level {
+	return minLevel(l.level.Level(), l.levels)
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < h.levels.levelOf(r.PC) {
+		return nil
+	}
+
+	return h.next.Handle(ctx, r)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{next: h.next.WithAttrs(attrs), levels: h.levels}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{next: h.next.WithGroup(name), levels: h.levels}
+}
+
+func (l *packageLevels) levelOf(pc uintptr) slog.Level {
+	if len(l.levels) == 0 || pc == 0 {
+		return l.level.Level()
+	}
+
+	cached, ok := l.cache.Load(pc)
+	if !ok {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		cached = l.match(packagePath(frame.Function))
+		l.cache.Store(pc, cached)
+	}
+
+	if level := cached.(packageLevel); level.matched { //nolint: forcetypeassert
+		return level.level
+	}
+
+	return l.level.Level()
+}
+
+// match returns the level of the longest configured package that is the
+// package itself or one of its parents, "internal/repository" matches
+// "github.com/b0shka/backend/internal/repository/postgresql". The packages
+// that match none take the default level.
+func (l *packageLevels) match(pkg string) packageLevel {
+	var (
+		level   packageLevel
+		matched string
+	)
+
+	for name, packageLvl := range l.levels {
+		path := "/" + pkg + "/"
+		if !strings.Contains(path, "/"+name+"/") || len(name) <= len(matched) {
+			continue
+		}
+
+		level, matched = packageLevel{level: packageLvl, matched: true}, name
+	}
+
+	return level
+}
+
+// packagePath cuts the function name off its fully qualified name, such as
+// "github.com/b0shka/backend/internal/worker.(*taskHandlers).ProcessTask".
+func packagePath(function string) string {
+	slash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[slash+1:], "."); dot >= 0 {
+		return function[:slash+1+dot]
+	}
+
+	return function
+}
@@ -1,42 +1,180 @@
 package logger
 
-import "github.com/sirupsen/logrus"
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
 
-type Logger interface {
-	Debug(msg string, params map[string]interface{})
-	Info(msg string, params map[string]interface{})
-	Warn(msg string, params map[string]interface{})
-	Error(msg string, params map[string]interface{})
+	"github.com/b0shka/backend/internal/config"
+	"github.com/b0shka/backend/internal/domain"
+	"github.com/b0shka/backend/pkg/redact"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Keys of the attributes carried by the context logger.
+const (
+	KeyRequestID = "request_id"
+	KeyUserID    = "user_id"
+	KeySessionID = "session_id"
+	KeyTaskID    = "task_id"
+	KeyTaskType  = "task_type"
+	KeyError     = "err"
+)
+
+type contextKey struct{}
+
+// New creates a logger writing to w. The format defaults to json in prod and
+// to text otherwise, the levels of the packages override the default level.
+// Every attribute is redacted before it is written, see redactAttr.
+func New(w io.Writer, cfg config.LoggerConfig, env string) (*slog.Logger, error) {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	levels := make(map[string]slog.Level, len(cfg.Levels))
+
+	for pkg, name := range cfg.Levels {
+		if levels[pkg], err = ParseLevel(name); err != nil {
+			return nil, err
+		}
+	}
+
+	format := cfg.Format
+	if format == "" {
+		format = FormatText
+		if env == config.EnvProd {
+			format = FormatJSON
+		}
+	}
+
+	packageLevels := newPackageLevels(level, levels)
+
+	opts := &slog.HandlerOptions{
+		AddSource: cfg.AddSource,
+		// the handler below filters the records by package.
+		Level:       packageLevels,
+		ReplaceAttr: redactAttr(redact.NewRedactor(cfg.RedactKeys...)),
+	}
+
+	var handler slog.Handler
+
+	switch format {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, opts)
+	case FormatText:
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnknownLogFormat, format)
+	}
+
+	return slog.New(newLevelHandler(handler, packageLevels)), nil
 }
 
-func Debug(msg ...interface{}) {
-	logrus.Debug(msg...)
+// Level returns the default level of a logger created by New.
+func Level(logger *slog.Logger) (slog.Level, bool) {
+	handler, ok := logger.Handler().(*levelHandler)
+	if !ok {
+		return 0, false
+	}
+
+	return handler.levels.level.Level(), true
+}
+
+// SetLevel changes the default level of a logger created by New and of the
+// loggers derived from it, the levels of the packages stay as configured.
+func SetLevel(logger *slog.Logger, level slog.Level) bool {
+	handler, ok := logger.Handler().(*levelHandler)
+	if !ok {
+		return false
+	}
+
+	handler.levels.level.Set(level)
+
+	return true
 }
 
-func Debugf(format string, args ...interface{}) {
-	logrus.Debugf(format, args...)
+// ParseLevel parses debug, info, warn and error, the empty level is info.
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if name == "" {
+		return slog.LevelInfo, nil
+	}
+
+	if err := level.UnmarshalText([]byte(strings.ToUpper(name))); err != nil {
+		return level, fmt.Errorf("%w: %s", domain.ErrUnknownLogLevel, name)
+	}
+
+	return level, nil
 }
 
-func Info(msg ...interface{}) {
-	logrus.Info(msg...)
+// WithContext stores the logger in the context.
+func WithContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
 }
 
-func Infof(format string, args ...interface{}) {
-	logrus.Infof(format, args...)
+// FromContext returns the logger of the context, or the default logger when
+// the context carries none.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+
+	return slog.Default()
 }
 
-func Warn(msg ...interface{}) {
-	logrus.Warn(msg...)
+// With stores the logger of the context with the attributes added, every
+// record logged with the returned context carries them.
+func With(ctx context.Context, args ...any) context.Context {
+	return WithContext(ctx, FromContext(ctx).With(args...))
 }
 
-func Warnf(format string, args ...interface{}) {
-	logrus.Warnf(format, args...)
+// Err is the attribute of an error, nil errors are omitted.
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+
+	return slog.String(KeyError, err.Error())
 }
 
-func Error(msg ...interface{}) {
-	logrus.Error(msg...)
+// redactAttr masks the sensitive keys, including the configured ones, and the
+// IP keys. Emails and tokens are masked in any string, structs and maps are
+// redacted field by field honoring the `redact:"true"` tags.
+func redactAttr(redactor *redact.Redactor) func([]string, slog.Attr) slog.Attr {
+	return func(groups []string, attr slog.Attr) slog.Attr {
+		if len(groups) == 0 {
+			switch attr.Key {
+			case slog.TimeKey, slog.LevelKey, slog.SourceKey:
+				return attr
+			}
+		}
+
+		switch attr.Value.Kind() { //nolint: exhaustive
+		case slog.KindString, slog.KindAny:
+			return slog.Any(attr.Key, redactor.Field(attr.Key, attr.Value.Any()))
+		default:
+			if redactor.IsSensitiveKey(attr.Key) {
+				return slog.String(attr.Key, redact.Mask)
+			}
+
+			return attr
+		}
+	}
 }
 
-func Errorf(format string, args ...interface{}) {
-	logrus.Errorf(format, args...)
+func minLevel(level slog.Level, levels map[string]slog.Level) slog.Level {
+	for _, l := range levels {
+		if l < level {
+			level = l
+		}
+	}
+
+	return level
 }
@@ -0,0 +1,79 @@
+package redact
+
+import (
+	"encoding/json"
+	"net"
+	"regexp"
+	"strings"
+)
+
+const Mask = "[REDACTED]"
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`) //nolint: gochecknoglobals
+
+// tokenPattern matches the JWT and PASETO tokens.
+var tokenPattern = regexp.MustCompile( //nolint: gochecknoglobals
+	`\beyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*|\bv[1-4]\.(?:local|public)\.[A-Za-z0-9_\-]+(?:\.[A-Za-z0-9_\-]+)?`,
+)
+
+// Email keeps the first character of the local part and the domain, so that
+// an address can still be told apart from others without being disclosed.
+func Email(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return Mask
+	}
+
+	return email[:1] + "***" + email[at:]
+}
+
+// IP keeps the network of the address, the /24 of IPv4 and the /48 of IPv6,
+// which is enough to tell the clients apart by region but not by host.
+func IP(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return Mask
+	}
+
+	if ipv4 := parsed.To4(); ipv4 != nil {
+		return ipv4.Mask(net.CIDRMask(24, 32)).String() //nolint: gomnd
+	}
+
+	return parsed.Mask(net.CIDRMask(48, 128)).String() //nolint: gomnd
+}
+
+// String masks every email address and token found in free text, e.g. error
+// messages.
+func String(s string) string {
+	s = tokenPattern.ReplaceAllString(s, Mask)
+
+	return emailPattern.ReplaceAllStringFunc(s, Email)
+}
+
+// IsSensitiveKey reports whether the value of the field must never be shown.
+func IsSensitiveKey(key string) bool {
+	switch strings.ToLower(key) {
+	case "secret_code", "secret", "password", "refresh_token", "access_token", "token",
+		"otp", "code", "authorization", "api_key", "x-api-key":
+		return true
+	default:
+		return false
+	}
+}
+
+// IsIPKey reports whether the field holds an IP address.
+func IsIPKey(key string) bool {
+	switch strings.ToLower(key) {
+	case "ip", "client_ip", "remote_addr", "ip_address":
+		return true
+	default:
+		return false
+	}
+}
+
+// JSON returns a copy of the document with sensitive fields masked and email
+// addresses redacted at any depth. A document that cannot be parsed is masked
+// as a whole.
+func JSON(data []byte) json.RawMessage {
+	return defaultRedactor.JSON(data)
+}
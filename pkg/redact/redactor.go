@@ -0,0 +1,218 @@
+package redact
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// TagName is the struct tag marking the fields that must never be shown,
+// `redact:"true"` masks the field whatever its name.
+const TagName = "redact"
+
+var defaultRedactor = NewRedactor() //nolint: gochecknoglobals
+
+// Redactor masks the values of the sensitive keys, the IP addresses of the IP
+// keys and the emails and tokens of free text. The keys are matched case
+// insensitively.
+type Redactor struct {
+	keys map[string]struct{}
+}
+
+// NewRedactor creates a redactor that also masks the given keys on top of the
+// default sensitive keys.
+func NewRedactor(keys ...string) *Redactor {
+	r := &Redactor{keys: make(map[string]struct{}, len(keys))}
+	for _, key := range keys {
+		r.keys[strings.ToLower(key)] = struct{}{}
+	}
+
+	return r
+}
+
+// IsSensitiveKey reports whether the value of the field must never be shown.
+func (r *Redactor) IsSensitiveKey(key string) bool {
+	if IsSensitiveKey(key) {
+		return true
+	}
+
+	_, ok := r.keys[strings.ToLower(key)]
+
+	return ok
+}
+
+// Field redacts the value of the field by its key and its content.
+func (r *Redactor) Field(key string, v any) any {
+	if r.IsSensitiveKey(key) {
+		return Mask
+	}
+
+	if s, ok := v.(string); ok && IsIPKey(key) {
+		return IP(s)
+	}
+
+	return r.Value(v)
+}
+
+// Value returns a copy of v that is safe to show. Structs are converted to
+// maps keyed by their json names, the fields tagged with `redact:"true"` and
+// the sensitive keys are masked at any depth. Values that marshal themselves,
+// such as time.Time or uuid.UUID, are kept as they are.
+func (r *Redactor) Value(v any) any {
+	if v == nil {
+		return nil
+	}
+
+	switch v := v.(type) {
+	case string:
+		return String(v)
+	case json.RawMessage:
+		return r.JSON(v)
+	case error:
+		return String(v.Error())
+	}
+
+	return r.value(reflect.ValueOf(v))
+}
+
+// JSON returns a copy of the document with sensitive fields masked, see
+// the package level JSON.
+func (r *Redactor) JSON(data []byte) json.RawMessage {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return json.RawMessage(`"` + Mask + `"`)
+	}
+
+	res, err := json.Marshal(r.Value(doc))
+	if err != nil {
+		return json.RawMessage(`"` + Mask + `"`)
+	}
+
+	return res
+}
+
+func (r *Redactor) value(v reflect.Value) any {
+	if marshals(v) {
+		return v.Interface()
+	}
+
+	switch v.Kind() { //nolint: exhaustive
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+
+		return r.value(v.Elem())
+	case reflect.String:
+		return String(v.String())
+	case reflect.Struct:
+		res := make(map[string]any, v.NumField())
+		r.structFields(v, res)
+
+		return res
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return v.Interface()
+		}
+
+		res := make(map[string]any, v.Len())
+
+		iter := v.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			res[key] = r.Field(key, valueOf(iter.Value()))
+		}
+
+		return res
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+
+		res := make([]any, v.Len())
+		for i := range res {
+			res[i] = r.value(v.Index(i))
+		}
+
+		return res
+	default:
+		return valueOf(v)
+	}
+}
+
+// structFields adds the exported fields of the struct to res, the fields of
+// the embedded structs are promoted as encoding/json does.
+func (r *Redactor) structFields(v reflect.Value, res map[string]any) {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, skip := jsonName(field)
+		if skip {
+			continue
+		}
+
+		fieldValue := v.Field(i)
+
+		if field.Anonymous && field.Tag.Get("json") == "" && fieldValue.Kind() == reflect.Struct {
+			r.structFields(fieldValue, res)
+
+			continue
+		}
+
+		if tagged, _ := field.Tag.Lookup(TagName); tagged == "true" {
+			res[name] = Mask
+
+			continue
+		}
+
+		res[name] = r.Field(name, valueOf(fieldValue))
+	}
+}
+
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, false
+	}
+
+	return field.Name, false
+}
+
+func valueOf(v reflect.Value) any {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+
+	return v.Interface()
+}
+
+func marshals(v reflect.Value) bool {
+	if !v.IsValid() || !v.CanInterface() {
+		return false
+	}
+
+	switch v.Interface().(type) {
+	case json.Marshaler, encoding.TextMarshaler:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,54 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Header is the header the request ID is accepted from and returned in.
+const Header = "X-Request-ID"
+
+// maxLength bounds the request IDs accepted from the clients, they end up in
+// the logs and in the database.
+const maxLength = 128
+
+type contextKey struct{}
+
+// New generates a request ID.
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid reports whether the request ID received from a client can be kept,
+// it must be short and made of the characters used by the common formats
+// such as UUID, ULID or the IDs of the load balancers.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':', r == '/', r == '+', r == '=':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// WithContext stores the request ID in the context.
+func WithContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID of the context, or an empty string when
+// the context carries none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+
+	return id
+}
@@ -0,0 +1,33 @@
+package requestid_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/b0shka/backend/pkg/requestid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValid(t *testing.T) {
+	tests := map[string]bool{
+		requestid.New():                  true,
+		"01HGW2N7EHJ0MZ0T6Y1KXQ3F4V":     true,
+		"Root=1-5759e988-bd862e3fe1be46": true,
+		"":                               false,
+		"id with spaces":                 false,
+		"id\nforged=log":                 false,
+		strings.Repeat("a", 129):         false,
+	}
+
+	for id, valid := range tests {
+		require.Equal(t, valid, requestid.Valid(id), id)
+	}
+}
+
+func TestContext(t *testing.T) {
+	require.Empty(t, requestid.FromContext(context.Background()))
+
+	ctx := requestid.WithContext(context.Background(), "request")
+	require.Equal(t, "request", requestid.FromContext(ctx))
+}
@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+var ErrForbiddenAddress = errors.New("webhook address is not public")
+
+// NewClient returns the client that delivers the webhooks. The endpoints are
+// given by the subscriptions, so the client only reaches public addresses:
+// the address is checked once resolved, when the connection is dialed, and
+// the redirects are not followed. The proxy of the environment is not used,
+// the dialed address would be the one of the proxy.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second, //nolint: gomnd
+		KeepAlive: 30 * time.Second, //nolint: gomnd
+		Control:   controlDial,
+	}
+
+	transport, _ := http.DefaultTransport.(*http.Transport)
+	transport = transport.Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// controlDial rejects the loopback, link-local, private and unspecified
+// addresses, the ones of the services next to the worker.
+func controlDial(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	addr := addrPort.Addr().Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() || isSharedAddress(addr) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addr)
+	}
+
+	return nil
+}
+
+// isSharedAddress reports whether the address is in the shared address space
+// of the carrier-grade NATs, IsPrivate does not cover it.
+func isSharedAddress(addr netip.Addr) bool {
+	return netip.MustParsePrefix("100.64.0.0/10").Contains(addr)
+}
@@ -0,0 +1,66 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestControlDial(t *testing.T) {
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{address: "93.184.216.34:443", allowed: true},
+		{address: "[2606:2800:220:1:248:1893:25c8:1946]:443", allowed: true},
+		{address: "127.0.0.1:9090"},
+		{address: "[::1]:9090"},
+		{address: "[::ffff:127.0.0.1]:9090"},
+		{address: "0.0.0.0:80"},
+		{address: "10.0.0.1:80"},
+		{address: "172.16.0.1:80"},
+		{address: "192.168.1.1:80"},
+		{address: "100.64.0.1:80"},
+		{address: "169.254.169.254:80"},
+		{address: "[fe80::1]:80"},
+		{address: "[fd00::1]:80"},
+		{address: "224.0.0.1:80"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.address, func(t *testing.T) {
+			err := controlDial("tcp", testCase.address, nil)
+			if testCase.allowed {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.True(t, errors.Is(err, ErrForbiddenAddress))
+		})
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, nil)
+	require.NoError(t, err)
+
+	_, err = NewClient(time.Second).Do(req) //nolint: bodyclose
+	require.True(t, errors.Is(err, ErrForbiddenAddress))
+}
+
+func TestNewClient_Redirect(t *testing.T) {
+	client := NewClient(time.Second)
+
+	require.Equal(t, http.ErrUseLastResponse, client.CheckRedirect(nil, nil))
+}
@@ -0,0 +1,24 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"type":"user.created"}`)
+
+	signature := Sign("secret", 1700000000, body)
+	require.Equal(t, "sha256=183b761865ab7e9c02fe7603937d181ce1482da954b1fecf912269e22500ac37", signature)
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"type":"user.created"}`)
+	signature := Sign("secret", 1700000000, body)
+
+	require.True(t, Verify("secret", 1700000000, body, signature))
+	require.False(t, Verify("other_secret", 1700000000, body, signature))
+	require.False(t, Verify("secret", 1700000001, body, signature))
+	require.False(t, Verify("secret", 1700000000, []byte(`{}`), signature))
+}